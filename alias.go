@@ -1,8 +1,60 @@
 package knoa
 
-import "github.com/ivancorrales/knoa/mutator"
+import (
+	"github.com/ivancorrales/knoa/inputter"
+	"github.com/ivancorrales/knoa/mutator"
+	"github.com/ivancorrales/knoa/schema"
+)
+
+type Format = inputter.Format
+
+type PathSyntax = mutator.PathSyntax
+
+type Path = mutator.Path
+
+type NodeContext = mutator.NodeContext
+
+type Operation = mutator.Operation
+
+type MergeOptions = mutator.MergeOptions
+
+type (
+	PathError            = mutator.PathError
+	TypeMismatchError    = mutator.TypeMismatchError
+	IndexOutOfRangeError = mutator.IndexOutOfRangeError
+	ApplyError           = mutator.ApplyError
+	TestFailedError      = mutator.TestFailedError
+	PatchError           = mutator.PatchError
+	ValidationError      = schema.ValidationError
+)
+
+const (
+	JSONFormat = inputter.JSONFormat
+	YAMLFormat = inputter.YAMLFormat
+)
+
+const (
+	DottedSyntax  = mutator.DottedSyntax
+	PointerSyntax = mutator.PointerSyntax
+)
+
+const (
+	ReplaceArrays      = mutator.ReplaceArrays
+	AppendArrays       = mutator.AppendArrays
+	MergeArraysByIndex = mutator.MergeArraysByIndex
+	MergeArraysByKey   = mutator.MergeArraysByKey
+)
+
+const (
+	OverrideOnConflict = mutator.OverrideOnConflict
+	KeepOnConflict     = mutator.KeepOnConflict
+	FailOnConflict     = mutator.FailOnConflict
+)
 
 var (
-	WithFuncPrefix   = mutator.WithFuncPrefix
-	WithStringPrefix = mutator.WithStringPrefix
+	WithFuncPrefix     = mutator.WithFuncPrefix
+	WithStringPrefix   = mutator.WithStringPrefix
+	WithArrayStrategy  = mutator.WithArrayStrategy
+	WithArrayKeyField  = mutator.WithArrayKeyField
+	WithConflictPolicy = mutator.WithConflictPolicy
 )
@@ -0,0 +1,186 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ivancorrales/knoa/internal"
+)
+
+type ChangeType int32
+
+const (
+	Added ChangeType = iota
+	Removed
+	Changed
+	TypeChanged
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	case TypeChanged:
+		return "type-changed"
+	default:
+		return "unknown"
+	}
+}
+
+type Change struct {
+	Type ChangeType
+	Path string
+	From any
+	To   any
+}
+
+func (c Change) String() string {
+	switch c.Type {
+	case Added:
+		return fmt.Sprintf("+ %s: %v", c.Path, c.To)
+	case Removed:
+		return fmt.Sprintf("- %s: %v", c.Path, c.From)
+	default:
+		return fmt.Sprintf("~ %s: %v -> %v", c.Path, c.From, c.To)
+	}
+}
+
+type Document[T any] interface {
+	Out() T
+}
+
+type differ struct {
+	keyField string
+}
+
+type Opt func(d *differ)
+
+// WithArrayKeyField matches the items of the arrays by the value of the given attribute
+// instead of by their position.
+func WithArrayKeyField(keyField string) func(d *differ) {
+	return func(d *differ) {
+		d.keyField = keyField
+	}
+}
+
+func Compare(from, to any, opts ...Opt) []Change {
+	d := &differ{}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d.compare("", internal.Normalize(from), internal.Normalize(to))
+}
+
+func CompareDocuments[T any](from, to Document[T], opts ...Opt) []Change {
+	return Compare(from.Out(), to.Out(), opts...)
+}
+
+func (d *differ) compare(path string, from, to any) []Change {
+	if internal.KindOf(from) != internal.KindOf(to) {
+		return []Change{{Type: TypeChanged, Path: path, From: from, To: to}}
+	}
+	switch fromValue := from.(type) {
+	case map[string]any:
+		return d.compareMaps(path, fromValue, to.(map[string]any))
+	case []any:
+		toValue, _ := to.([]any)
+		if d.keyField != "" && d.hasKeys(fromValue) && d.hasKeys(toValue) {
+			return d.compareArraysByKey(path, fromValue, toValue)
+		}
+		return d.compareArraysByIndex(path, fromValue, toValue)
+	default:
+		if internal.Equal(from, to) {
+			return nil
+		}
+		return []Change{{Type: Changed, Path: path, From: from, To: to}}
+	}
+}
+
+func (d *differ) compareMaps(path string, from, to map[string]any) []Change {
+	keys := make([]string, 0, len(from)+len(to))
+	for k := range from {
+		keys = append(keys, k)
+	}
+	for k := range to {
+		if _, found := from[k]; !found {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var changes []Change
+	for _, k := range keys {
+		fromValue, inFrom := from[k]
+		toValue, inTo := to[k]
+		childPath := internal.AttributePath(path, k)
+		switch {
+		case !inFrom:
+			changes = append(changes, Change{Type: Added, Path: childPath, To: toValue})
+		case !inTo:
+			changes = append(changes, Change{Type: Removed, Path: childPath, From: fromValue})
+		default:
+			changes = append(changes, d.compare(childPath, fromValue, toValue)...)
+		}
+	}
+	return changes
+}
+
+func (d *differ) compareArraysByIndex(path string, from, to []any) []Change {
+	var changes []Change
+	for i := 0; i < len(from) || i < len(to); i++ {
+		childPath := internal.IndexPath(path, i)
+		switch {
+		case i >= len(from):
+			changes = append(changes, Change{Type: Added, Path: childPath, To: to[i]})
+		case i >= len(to):
+			changes = append(changes, Change{Type: Removed, Path: childPath, From: from[i]})
+		default:
+			changes = append(changes, d.compare(childPath, from[i], to[i])...)
+		}
+	}
+	return changes
+}
+
+func (d *differ) compareArraysByKey(path string, from, to []any) []Change {
+	var changes []Change
+	for i, fromItem := range from {
+		if d.indexByKey(to, fromItem) < 0 {
+			changes = append(changes, Change{Type: Removed, Path: internal.IndexPath(path, i), From: fromItem})
+		}
+	}
+	for i, toItem := range to {
+		j := d.indexByKey(from, toItem)
+		if j < 0 {
+			changes = append(changes, Change{Type: Added, Path: internal.IndexPath(path, i), To: toItem})
+			continue
+		}
+		changes = append(changes, d.compare(internal.IndexPath(path, i), from[j], toItem)...)
+	}
+	return changes
+}
+
+func (d *differ) hasKeys(items []any) bool {
+	for _, item := range items {
+		value, ok := item.(map[string]any)
+		if !ok {
+			return false
+		}
+		if _, found := value[d.keyField]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *differ) indexByKey(items []any, item any) int {
+	key := item.(map[string]any)[d.keyField]
+	for i := range items {
+		if internal.Equal(items[i].(map[string]any)[d.keyField], key) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,81 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_compare(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Opt
+		from any
+		to   any
+		want []Change
+	}{
+		{
+			name: "Equal documents",
+			from: map[string]any{"age": 20, "tags": []any{"a"}},
+			to:   map[string]any{"age": float64(20), "tags": []string{"a"}},
+			want: nil,
+		},
+		{
+			name: "Added, removed and changed attributes",
+			from: map[string]any{"firstname": "Jane", "age": 20, "partner": map[string]any{"age": 32}},
+			to:   map[string]any{"firstname": "Jane", "lastname": "Doe", "partner": map[string]any{"age": 33}},
+			want: []Change{
+				{Type: Removed, Path: "age", From: 20},
+				{Type: Added, Path: "lastname", To: "Doe"},
+				{Type: Changed, Path: "partner.age", From: 32, To: 33},
+			},
+		},
+		{
+			name: "Type changes",
+			from: map[string]any{"ports": []any{80}, "replicas": "1", "annotations": map[string]any{"a.b": nil}},
+			to:   map[string]any{"ports": "80", "replicas": 1, "annotations": map[string]any{"a.b": true}},
+			want: []Change{
+				{Type: TypeChanged, Path: "annotations.\"a.b\"", From: nil, To: true},
+				{Type: TypeChanged, Path: "ports", From: []any{80}, To: "80"},
+				{Type: TypeChanged, Path: "replicas", From: "1", To: 1},
+			},
+		},
+		{
+			name: "Arrays matched by index",
+			from: []any{map[string]any{"name": "Tim"}, map[string]any{"name": "Bob"}},
+			to:   []any{map[string]any{"name": "Bob"}},
+			want: []Change{
+				{Type: Changed, Path: "[0].name", From: "Tim", To: "Bob"},
+				{Type: Removed, Path: "[1]", From: map[string]any{"name": "Bob"}},
+			},
+		},
+		{
+			name: "Arrays matched by key field",
+			opts: []Opt{WithArrayKeyField("name")},
+			from: map[string]any{"siblings": []any{
+				map[string]any{"name": "Tim", "age": 29},
+				map[string]any{"name": "Bob", "age": 40},
+			}},
+			to: map[string]any{"siblings": []any{
+				map[string]any{"name": "Bob", "age": 41},
+				map[string]any{"name": "Jane", "age": 20},
+			}},
+			want: []Change{
+				{Type: Removed, Path: "siblings[0]", From: map[string]any{"name": "Tim", "age": 29}},
+				{Type: Changed, Path: "siblings[0].age", From: 40, To: 41},
+				{Type: Added, Path: "siblings[1]", To: map[string]any{"name": "Jane", "age": 20}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Compare(tt.from, tt.to, tt.opts...))
+		})
+	}
+}
+
+func Test_change_string(t *testing.T) {
+	assert.Equal(t, "+ lastname: Doe", Change{Type: Added, Path: "lastname", To: "Doe"}.String())
+	assert.Equal(t, "- age: 20", Change{Type: Removed, Path: "age", From: 20}.String())
+	assert.Equal(t, "~ partner.age: 32 -> 33", Change{Type: Changed, Path: "partner.age", From: 32, To: 33}.String())
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_typedApply() {
+	k := knoa.FromJSON([]byte(`{"name":"jane","age":41,"price":10.5,"tags":["a","b"],"items":[{"id":1},{"id":2}]}`))
+	k.Apply(
+		"name", strings.ToUpper,
+		"age", func(age int) int { return age + 1 },
+		"price", func(price float64) (float64, error) {
+			if price < 0 {
+				return 0, errors.New("negative price")
+			}
+			return price * 2, nil
+		},
+		"tags", func(tags []string) string { return strings.Join(tags, ",") },
+		"items[*].id", func(path string, id int) string { return fmt.Sprintf("%s=%d", path, id) },
+	)
+	fmt.Println(k.JSON())
+
+	m := knoa.Map().Set("age", 1.5).Apply("age", func(age int) int { return age + 1 })
+	fmt.Println(m.JSON())
+	fmt.Println(m.Error())
+	// Output:
+	// {"age":42,"items":[{"id":"items[0].id=1"},{"id":"items[1].id=2"}],"name":"JANE","price":21,"tags":"a,b"}
+	// {"age":1.5}
+	// apply on 'age' failed: cannot use '1.5' as 'int'
+}
+
+func Example_applyCtx() {
+	k := knoa.FromJSON([]byte(`{"currency":"EUR","items":[{"price":2.5,"qty":4},{"price":10,"qty":1}]}`))
+	k.ApplyCtx("items[*].total", func(ctx knoa.NodeContext) any {
+		item := ctx.Parent.(map[string]any)
+		return item["price"].(float64) * item["qty"].(float64)
+	}).ApplyCtx("items[*].label", func(ctx knoa.NodeContext) any {
+		root := ctx.Root().(map[string]any)
+		return fmt.Sprintf("#%s (%s)", ctx.Indexes[0], root["currency"])
+	})
+	fmt.Println(k.JSON())
+	// Output:
+	// {"currency":"EUR","items":[{"label":"#0 (EUR)","price":2.5,"qty":4,"total":10},{"label":"#1 (EUR)","price":10,"qty":1,"total":10}]}
+}
@@ -21,9 +21,9 @@ func ExampleArrayStrict() {
 			fmt.Println(r)
 		}
 	}()
-	knoa.Array(knoa.WithStrictMode(true)).Set("[a]", "Jane").JSON()
+	knoa.Array(knoa.WithStrictMode(true), knoa.WithPanicMode(true)).Set("[a]", "Jane").JSON()
 	// Output:
-	// invalid Path  '[a]'. Path doesn't match defined format
+	// invalid path '[a]': invalid index 'a' at column 1
 }
 
 // Create and array and add/modify entries
@@ -96,3 +96,29 @@ func ExampleArraySetAsteriskAndIndexV2() {
 	// [{"age":22,"firstname":"Jane"},{"age":22,"firstname":"Tom"}]
 	// [{"age":30,"firstname":"Jane"},{"age":30,"firstname":"Tom"}]
 }
+
+func Example_appendPrependAndInsert() {
+	k := knoa.FromJSON([]byte(`{"items":["b","c"],"tags":[]}`))
+	k.Set("items[+]", "d", "items[^]", "a", "tags[-]", "x").Insert("items", 2, "b1", "b2")
+	fmt.Println(k.JSON())
+	fmt.Println(k.JSONPatch())
+
+	a := knoa.Array().Set("[+]", 1, "[+]", 3).Insert("", 1, 2)
+	fmt.Println(a.JSON())
+	// Output:
+	// {"items":["a","b","b1","b2","c","d"],"tags":["x"]}
+	// [{"op":"add","path":"/items/2","value":"d"},{"op":"add","path":"/items/0","value":"a"},{"op":"add","path":"/tags/0","value":"x"},{"op":"add","path":"/items/2","value":"b1"},{"op":"add","path":"/items/3","value":"b2"}]
+	// [1,2,3]
+}
+
+func Example_negativeIndexesAndSlices() {
+	k := knoa.FromJSON([]byte(`{"items":[{"id":1},{"id":2},{"id":3},{"id":4},{"id":5}],"tags":["a","b","c","d","e"]}`))
+	k.Set("items[-1].last", true, "items[1:3].selected", true).
+		Set("tags[::2]", "x").
+		Unset("tags[:2]")
+	fmt.Println(k.JSON())
+	fmt.Println(k.Get("items[-2].id"))
+	// Output:
+	// {"items":[{"id":1},{"id":2,"selected":true},{"id":3,"selected":true},{"id":4},{"id":5,"last":true}],"tags":["x","d","x"]}
+	// 4 true
+}
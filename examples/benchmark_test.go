@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func newBenchmarkDocument(sets int) knoa.Knoa[map[string]any] {
+	k := knoa.Map()
+	for i := 0; i < sets; i++ {
+		k.Set(fmt.Sprintf("items[%d].name", i), fmt.Sprintf("item-%d", i), fmt.Sprintf("items[%d].tags[+]", i), "new")
+	}
+	return k
+}
+
+// BenchmarkRepeatedJSON serializes the same document once and again.
+func BenchmarkRepeatedJSON(b *testing.B) {
+	for _, sets := range []int{10, 100, 500} {
+		b.Run(fmt.Sprintf("sets=%d", sets), func(b *testing.B) {
+			k := newBenchmarkDocument(sets)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = k.JSON()
+			}
+		})
+	}
+}
+
+// BenchmarkInterleavedSetAndGet reads the document after every change.
+func BenchmarkInterleavedSetAndGet(b *testing.B) {
+	for _, sets := range []int{10, 100, 500} {
+		b.Run(fmt.Sprintf("sets=%d", sets), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				k := knoa.Map()
+				for j := 0; j < sets; j++ {
+					k.Set(fmt.Sprintf("items[%d].name", j), "item")
+					_, _ = k.Get(fmt.Sprintf("items[%d].name", j))
+				}
+			}
+		})
+	}
+}
+
+func Example_cachedOutput() {
+	k := knoa.Map().Set("a.b", 1, "list", []string{"x"})
+	out := k.Out()
+	out["a"] = "modified"
+	fmt.Println(k.JSON())
+
+	k.Set("a.b.c", 2).Set("list[+]", "y")
+	fmt.Println(k.JSON())
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+	// Output:
+	// {"a":{"b":1},"list":["x"]}
+	// {"a":{"b":1},"list":["x","y"]}
+	// {"a":{"b":1},"list":["x","y"]}
+	// cannot resolve 'c' at 'a.b': expected map but found number
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_clone() {
+	base := knoa.Map().Set("replicas", 1, "image", "api:1.0")
+	tenant := base.Clone().Set("tenant", "acme", "replicas", 3)
+	base.Unset("image")
+	fmt.Println(base.JSON())
+	fmt.Println(tenant.JSON())
+	// Output:
+	// {"replicas":1}
+	// {"image":"api:1.0","replicas":3,"tenant":"acme"}
+}
+
+func Example_immutableMode() {
+	template := knoa.Map(knoa.WithImmutableMode(true)).Set("replicas", 1, "labels.app", "api")
+	acme := template.Set("labels.tenant", "acme")
+	globex := template.Set("labels.tenant", "globex", "replicas", 2)
+	template.Unset("labels")
+	fmt.Println(template.JSON())
+	fmt.Println(acme.JSON())
+	fmt.Println(globex.JSON())
+	// Output:
+	// {"labels":{"app":"api"},"replicas":1}
+	// {"labels":{"app":"api","tenant":"acme"},"replicas":1}
+	// {"labels":{"app":"api","tenant":"globex"},"replicas":2}
+}
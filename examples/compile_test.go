@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_compilePath() {
+	image, err := knoa.CompilePath("spec.containers[0].image")
+	if err != nil {
+		panic(err)
+	}
+	debug, _ := knoa.CompilePath("spec.debug")
+	for _, version := range []string{"1.0", "2.0"} {
+		k := knoa.Map().Set(image, "api:"+version, debug, true).UnsetPaths(debug)
+		fmt.Println(k.JSON())
+		fmt.Println(k.GetString(image))
+	}
+
+	_, err = knoa.CompilePath("spec.containers[0")
+	fmt.Println(err)
+	// Output:
+	// {"spec":{"containers":[{"image":"api:1.0"}]}}
+	// api:1.0 true
+	// {"spec":{"containers":[{"image":"api:2.0"}]}}
+	// api:2.0 true
+	// invalid path 'spec.containers[0': missing ']' at column 16
+}
+
+func Example_unsetPaths() {
+	names := []string{"spec.debug", "spec.owner"}
+	k := knoa.Map().Set("spec.debug", true, "spec.owner", "platform", "spec.image", "api").Unset(names...)
+	fmt.Println(k.JSON())
+	// Output:
+	// {"spec":{"image":"api"}}
+}
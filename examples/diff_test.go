@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+	"github.com/ivancorrales/knoa/diff"
+)
+
+func Example_diff() {
+	current := knoa.Map().Set("replicas", 1, "image", "api:1.0", "ports", []int{80, 443})
+	desired := knoa.Map().Set("replicas", 3, "image", "api:1.0", "ports", []int{80}, "labels.tier", "backend")
+	for _, change := range diff.CompareDocuments[map[string]any](current, desired) {
+		fmt.Println(change)
+	}
+	// Output:
+	// + labels: map[tier:backend]
+	// - ports[1]: 443
+	// ~ replicas: 1 -> 3
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_typedErrors() {
+	k := knoa.Map(knoa.WithStrictMode(true)).
+		Set("name", "api", 20, "ignored", "labels[", "x").
+		Set("name.first", "Jane").
+		Set("ports[-3]", 8080).
+		Apply("name", func(n int) int { return n + 1 })
+	fmt.Println(k.JSON())
+
+	var pathErr *knoa.PathError
+	if errors.As(k.Error(), &pathErr) {
+		fmt.Printf("path: %s, reason: %s\n", pathErr.Path, pathErr.Reason)
+	}
+	var mismatchErr *knoa.TypeMismatchError
+	if errors.As(k.Error(), &mismatchErr) {
+		fmt.Printf("path: %s, segment: %s, expected: %s, actual: %s\n",
+			mismatchErr.Path, mismatchErr.Segment, mismatchErr.Expected, mismatchErr.Actual)
+	}
+	var rangeErr *knoa.IndexOutOfRangeError
+	if errors.As(k.Error(), &rangeErr) {
+		fmt.Printf("path: %s, index: %d, length: %d\n", rangeErr.Path, rangeErr.Index, rangeErr.Length)
+	}
+	var applyErr *knoa.ApplyError
+	if errors.As(k.Error(), &applyErr) {
+		fmt.Printf("path: %s, cause: %v\n", applyErr.Path, applyErr.Err)
+	}
+	fmt.Println(k.Error())
+	// Output:
+	// {"name":"api"}
+	// path: 20, reason: paths must be strings
+	// path: name, segment: first, expected: map, actual: string
+	// path: ports, index: -3, length: 0
+	// path: name, cause: cannot use 'api' as 'int'
+	// invalid path '20': paths must be strings
+	// invalid path 'labels[': missing ']' at column 7
+	// cannot resolve 'first' at 'name': expected map but found string
+	// index -3 out of range at 'ports': the array has 0 items
+	// apply on 'name' failed: cannot use 'api' as 'int'
+}
+
+func Example_panicMode() {
+	defer func() {
+		if r := recover(); r != nil {
+			var mismatchErr *knoa.TypeMismatchError
+			fmt.Println(errors.As(r.(error), &mismatchErr), r)
+		}
+	}()
+	knoa.Map(knoa.WithPanicMode(true)).Set("name", "api", "name[0]", "x").JSON()
+	// Output:
+	// true cannot resolve '[0]' at 'name': expected array but found string
+}
+
+func Example_patchErrors() {
+	k := knoa.Map().Set("name", "api").Patch([]byte(`[{"op":"test","path":"/name","value":"proxy"}]`))
+	fmt.Println(k.JSON())
+	var testErr *knoa.TestFailedError
+	if errors.As(k.Error(), &testErr) {
+		fmt.Printf("path: %s, expected: %v, actual: %v\n", testErr.Path, testErr.Expected, testErr.Actual)
+	}
+
+	k = knoa.Map().Patch([]byte(`[{"op":"rename","path":"/name"}]`))
+	var patchErr *knoa.PatchError
+	if errors.As(k.Error(), &patchErr) {
+		fmt.Printf("op: %s, reason: %s\n", patchErr.Op, patchErr.Reason)
+	}
+	// Output:
+	// {"name":"api"}
+	// path: name, expected: proxy, actual: api
+	// op: rename, reason: unsupported operation
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_filterPredicates() {
+	k := knoa.FromJSON([]byte(`{
+		"users":[{"name":"John","age":40},{"name":"Jane","age":25,"email":"jane@mail.com"},{"name":"Tom","age":35}],
+		"items":[{"type":"disk","size":500},{"type":"cpu"},{"type":"disk","size":100}]
+	}`))
+	k.Set("users[?(@.age > 30)].active", true, "users[?(!@.email)].email", "unknown").
+		Unset(`items[?(@.type == "disk" && @.size < 200)]`)
+	fmt.Println(k.JSON())
+	fmt.Println(k.Get(`users[?(@.name == "Tom" || @.name == "Jane")].age`))
+	// Output:
+	// {"items":[{"size":500,"type":"disk"},{"type":"cpu"}],"users":[{"active":true,"age":40,"email":"unknown","name":"John"},{"age":25,"email":"jane@mail.com","name":"Jane"},{"active":true,"age":35,"email":"unknown","name":"Tom"}]}
+	// [25 35] true
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_get() {
+	k := knoa.Map().Set("firstname", "John", "age", 20, "siblings", []Person{
+		{
+			Firstname: "Tim",
+			Age:       29,
+		}, {
+			Firstname: "Bob",
+			Age:       40,
+		},
+	})
+	fmt.Println(k.Get("siblings[1].firstname"))
+	fmt.Println(k.Get("siblings[*].age"))
+	fmt.Println(k.Get("lastname"))
+	fmt.Println(k.Has("siblings[0]"), k.Has("siblings[2]"))
+	// Output:
+	// Bob true
+	// [29 40] true
+	// <nil> false
+	// true false
+}
+
+func Example_typedGetters() {
+	k := knoa.FromMap(map[string]any{
+		"firstname": "Jane",
+		"age":       float64(33),
+		"height":    1.68,
+		"enabled":   true,
+		"languages": []any{"English", "Irish"},
+		"address":   map[string]any{"city": "Dublin"},
+	})
+	fmt.Println(k.GetString("firstname"))
+	fmt.Println(k.GetInt("age"))
+	fmt.Println(k.GetInt("height"))
+	fmt.Println(k.GetFloat("height"))
+	fmt.Println(k.GetBool("enabled"))
+	fmt.Println(k.GetSlice("languages"))
+	fmt.Println(k.GetMap("address"))
+	fmt.Println(k.GetString("age"))
+	// Output:
+	// Jane true
+	// 33 true
+	// 0 false
+	// 1.68 true
+	// true true
+	// [English Irish] true
+	// map[city:Dublin] true
+	//  false
+}
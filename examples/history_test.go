@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_undoAndRedo() {
+	k := knoa.Map().Set("name", "api", "replicas", 1)
+	k.Set("replicas", 3).Unset("name").Apply("replicas", func(n int) int { return n * 2 })
+	fmt.Println(k.JSON())
+	k.Undo().Undo()
+	fmt.Println(k.JSON())
+	k.Redo()
+	fmt.Println(k.JSON())
+	k.Set("image", "api:2.0").Redo()
+	fmt.Println(k.JSON())
+	for _, entry := range k.History() {
+		fmt.Println(entry.Operation, entry.Paths)
+	}
+	// Output:
+	// {"replicas":6}
+	// {"name":"api","replicas":3}
+	// {"replicas":3}
+	// {"image":"api:2.0","replicas":3}
+	// set [name replicas]
+	// set [replicas]
+	// unset [name]
+	// set [image]
+}
+
+func Example_snapshots() {
+	k := knoa.Map().Set("replicas", 1)
+	k.Snapshot("v1")
+	k.Set("replicas", 3, "image", "api:2.0")
+	k.Tx(func(tx knoa.Knoa[map[string]any]) error {
+		tx.Move("image", "containers[0].image")
+		return nil
+	})
+	k.Snapshot("v2")
+	fmt.Println(k.JSON())
+	k.Restore("v1")
+	fmt.Println(k.JSON())
+	k.Restore("v2")
+	fmt.Println(k.JSON())
+	fmt.Println(k.History())
+	k.Restore("v3")
+	fmt.Println(k.Error())
+	// Output:
+	// {"containers":[{"image":"api:2.0"}],"replicas":3}
+	// {"replicas":1}
+	// {"containers":[{"image":"api:2.0"}],"replicas":3}
+	// [{set [replicas]} {set [replicas image]} {tx [image containers[0].image]} {restore []} {restore []}]
+	// snapshot 'v3' not found
+}
+
+func Example_undoRestore() {
+	k := knoa.Map().Set("replicas", 1).Snapshot("v1")
+	k.Set("replicas", 3, "image", "api:2.0")
+	k.Restore("v1")
+	fmt.Println(k.JSON())
+	k.Undo()
+	fmt.Println(k.JSON())
+	k.Redo()
+	fmt.Println(k.JSON())
+	fmt.Println(k.History()[2].Operation)
+	// Output:
+	// {"replicas":1}
+	// {"image":"api:2.0","replicas":3}
+	// {"replicas":1}
+	// restore
+}
+
+func Example_undoInImmutableMode() {
+	v1 := knoa.Map(knoa.WithImmutableMode(true)).Set("replicas", 1)
+	v2 := v1.Set("replicas", 2)
+	previous := v2.Undo()
+	fmt.Println(v2.JSON())
+	fmt.Println(previous.JSON())
+	fmt.Println(previous.Redo().JSON())
+	// Output:
+	// {"replicas":2}
+	// {"replicas":1}
+	// {"replicas":2}
+}
+
+func Example_undoErrors() {
+	k := knoa.Map().Set("replicas", "one").Apply("replicas", func(n int) int { return n * 2 })
+	k.Set("image", "api:2.0")
+	fmt.Println(k.JSON())
+	k.Undo()
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+	k.Undo()
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+	// Output:
+	// {"image":"api:2.0","replicas":"one"}
+	// {"replicas":"one"}
+	// apply on 'replicas' failed: cannot use 'one' as 'int'
+	// {"replicas":"one"}
+	// <nil>
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_fromJSON() {
+	k := knoa.FromJSON([]byte(`{"firstname":"Jane","siblings":[{"firstname":"Tim","age":29}],"tags":[]}`))
+	k.Set("siblings[0].age", 30, "lastname", "Doe")
+	fmt.Println(k.JSON())
+	fmt.Println(knoa.FromJSON([]byte(`["red","blue"]`)).Set("[2]", "green").JSON())
+	fmt.Println(knoa.FromJSON([]byte(`"red"`)).Error())
+	// Output:
+	// {"firstname":"Jane","lastname":"Doe","siblings":[{"age":30,"firstname":"Tim"}],"tags":[]}
+	// ["red","blue","green"]
+	// unsupported root type 'string'
+}
+
+func Example_fromYAML() {
+	k := knoa.FromYAML([]byte(`
+firstname: Jane
+ports:
+  80: http
+  443: https
+`))
+	k.Set("lastname", "Doe")
+	fmt.Println(k.JSON())
+	// Output:
+	// {"firstname":"Jane","lastname":"Doe","ports":{"443":"https","80":"http"}}
+}
+
+func Example_fromReader() {
+	k := knoa.FromReader(strings.NewReader(`{"firstname":"Jane"}`), knoa.JSONFormat)
+	fmt.Println(k.Set("age", 20).YAML())
+	// Output:
+	// age: 20
+	// firstname: Jane
+}
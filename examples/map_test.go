@@ -22,9 +22,9 @@ func ExampleFromScratchWithStrictModeEnabled() {
 			fmt.Println(r)
 		}
 	}()
-	knoa.Map(knoa.WithStrictMode(true)).Set("firstname.$", "Jane").JSON()
+	knoa.Map(knoa.WithStrictMode(true), knoa.WithPanicMode(true)).Set("firstname.$", "Jane").JSON()
 	// Output:
-	// invalid Path  'firstname.$'. Path doesn't match defined format
+	// invalid path 'firstname.$': attribute '$' doesn't match the defined format at column 11
 }
 
 // Ignore those attributes that don't match the provided format
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_merge() {
+	defaults := knoa.Map().Set("replicas", 1, "image", "api:1.0", "ports", []int{80})
+	overlay := map[string]any{
+		"replicas": 3,
+		"ports":    []int{443},
+	}
+	k := knoa.Map().Merge(defaults).Merge(overlay, knoa.WithArrayStrategy(knoa.AppendArrays))
+	fmt.Println(k.JSON())
+	fmt.Println(k.JSON())
+	// Output:
+	// {"image":"api:1.0","ports":[80,443],"replicas":3}
+	// {"image":"api:1.0","ports":[80,443],"replicas":3}
+}
+
+func Example_mergeByKey() {
+	k := knoa.FromJSON([]byte(`{"containers":[{"name":"api","image":"api:1.0"},{"name":"proxy","image":"nginx"}]}`))
+	k.Merge(map[string]any{
+		"containers": []any{
+			map[string]any{"name": "api", "image": "api:1.1"},
+			map[string]any{"name": "sidecar", "image": "envoy"},
+		},
+	}, knoa.WithArrayKeyField("name"))
+	fmt.Println(k.JSON())
+	k.Merge(map[string]any{"containers": "none"}, knoa.WithConflictPolicy(knoa.FailOnConflict))
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+	// Output:
+	// {"containers":[{"image":"api:1.1","name":"api"},{"image":"nginx","name":"proxy"},{"image":"envoy","name":"sidecar"}]}
+	// {"containers":[{"image":"api:1.1","name":"api"},{"image":"nginx","name":"proxy"},{"image":"envoy","name":"sidecar"}]}
+	// invalid value at 'containers': expected array but found string
+}
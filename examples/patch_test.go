@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_patch() {
+	k := knoa.FromJSON([]byte(`{"name":"api","ports":[80,443],"labels":{"tier":"backend"}}`))
+	k.Patch([]byte(`[
+		{"op":"add","path":"/ports/-","value":8080},
+		{"op":"move","from":"/labels/tier","path":"/tier"},
+		{"op":"test","path":"/name","value":"api"}
+	]`))
+	fmt.Println(k.JSON())
+	k.Patch([]byte(`[
+		{"op":"remove","path":"/ports"},
+		{"op":"test","path":"/name","value":"proxy"}
+	]`))
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+	// Output:
+	// {"labels":{},"name":"api","ports":[80,443,8080],"tier":"backend"}
+	// {"labels":{},"name":"api","ports":[80,443,8080],"tier":"backend"}
+	// test operation on '/name' failed: test failed at 'name': expected 'proxy' but found 'api'
+}
+
+func Example_jsonPatch() {
+	k := knoa.FromJSON([]byte(`{"name":"api","replicas":1,"ports":[80,443]}`))
+	k.Set("replicas", 3, "labels.tier", "backend").Unset("ports[0]")
+	fmt.Println(k.JSONPatch())
+	// Output:
+	// [{"op":"replace","path":"/replicas","value":3},{"op":"add","path":"/labels","value":{"tier":"backend"}},{"op":"remove","path":"/ports/0"}]
+}
+
+func Example_mergePatch() {
+	k := knoa.FromJSON([]byte(`{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"]}`))
+	k.MergePatch(map[string]any{
+		"title":  "Hello!",
+		"author": map[string]any{"familyName": nil},
+		"tags":   []any{"example"},
+	})
+	fmt.Println(k.JSON())
+	// Output:
+	// {"author":{"givenName":"John"},"tags":["example"],"title":"Hello!"}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_quotedKeys() {
+	k := knoa.Map().Set(
+		`metadata.annotations["app.kubernetes.io/name"]`, "api",
+		`metadata.'display name'`, "My API",
+		`metadata.labels."say \"hi\""`, true,
+	)
+	fmt.Println(k.JSON())
+
+	k = knoa.Map().Set("metadata.labels[app", "api")
+	fmt.Println(k.Error())
+	// Output:
+	// {"metadata":{"annotations":{"app.kubernetes.io/name":"api"},"display name":"My API","labels":{"say \"hi\"":true}}}
+	// invalid path 'metadata.labels[app': missing ']' at column 16
+}
+
+func Example_escapedKeys() {
+	k := knoa.Map().Set(`metadata.labels.app\.kubernetes\.io/name`, "api", `metadata.labels.\*`, true)
+	fmt.Println(k.JSON())
+	fmt.Println(k.GetString(`metadata.labels.app\.kubernetes\.io/name`))
+	// Output:
+	// {"metadata":{"labels":{"*":true,"app.kubernetes.io/name":"api"}}}
+	// api true
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_pointerSyntax() {
+	k := knoa.Map(knoa.WithPathSyntax(knoa.PointerSyntax)).
+		Set("/spec/containers/0/image", "api:1.0", "/spec/containers/0/args/-", "--verbose").
+		Set("/metadata/annotations/app.kubernetes.io~1name", "api", "/metadata/tmp", true).
+		Unset("/metadata/tmp")
+	fmt.Println(k.JSON())
+	fmt.Println(k.GetString("/spec/containers/0/image"))
+	// Output:
+	// {"metadata":{"annotations":{"app.kubernetes.io/name":"api"}},"spec":{"containers":[{"args":["--verbose"],"image":"api:1.0"}]}}
+	// api:1.0 true
+}
+
+func Example_pointerSyntaxInsert() {
+	k := knoa.Map(knoa.WithPathSyntax(knoa.PointerSyntax)).
+		Set("/items", []any{1, 4}).
+		Insert("/items", 1, 2, 3)
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+	// Output:
+	// {"items":[1,2,3,4]}
+	// <nil>
+}
+
+func Example_pointerSyntaxEmptyKeys() {
+	k := knoa.FromJSON([]byte(`{"":1,"spec":{"":2}}`), knoa.WithPathSyntax(knoa.PointerSyntax))
+	k.Set("/", 3, "/spec/", 4)
+	fmt.Println(k.JSON())
+	fmt.Println(k.Get("/spec/"))
+
+	d := knoa.FromJSON([]byte(`{"":1,"spec":{"":2}}`))
+	d.Set(`['']`, 5, `spec['']`, 6)
+	fmt.Println(d.JSON())
+	// Output:
+	// {"":3,"spec":{"":4}}
+	// 4 true
+	// {"":5,"spec":{"":6}}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_operationsAndReplay() {
+	changes := knoa.Map().
+		Set("replicas", 3, "labels.tier", "backend").
+		Unset("owner").
+		Rename("image", "container")
+
+	var log bytes.Buffer
+	encoder := json.NewEncoder(&log)
+	for _, op := range changes.Operations() {
+		_ = encoder.Encode(op)
+	}
+	fmt.Print(log.String())
+
+	var ops []knoa.Operation
+	scanner := bufio.NewScanner(&log)
+	for scanner.Scan() {
+		var op knoa.Operation
+		_ = json.Unmarshal(scanner.Bytes(), &op)
+		ops = append(ops, op)
+	}
+	base := knoa.FromMap(map[string]any{"owner": "platform", "image": "api:1.0", "replicas": 1})
+	fmt.Println(knoa.Replay(base, ops).JSON())
+	// Output:
+	// {"op":"set","path":"replicas","value":3}
+	// {"op":"set","path":"labels.tier","value":"backend"}
+	// {"op":"unset","path":"owner"}
+	// {"op":"move","path":"container","from":"image"}
+	// {"container":"api:1.0","labels":{"tier":"backend"},"replicas":3}
+}
+
+func Example_replayFailure() {
+	base := knoa.Map().Set("replicas", 1)
+	ops := knoa.Map().
+		Set("replicas", 2).
+		Apply("replicas", func(n int) int { return n * 2 }).
+		Operations()
+	fmt.Println(knoa.Replay(base, ops).JSON())
+	fmt.Println(base.Error())
+	// Output:
+	// {"replicas":1}
+	// transaction rolled back: operation 'apply' can't be replayed: the func applied on 'replicas' is not serializable
+}
+
+func Example_replayPointerTokens() {
+	k := knoa.FromJSON([]byte(`{"ports":{"80":"http"},"items":["a"]}`), knoa.WithPathSyntax(knoa.PointerSyntax)).
+		Set("/ports/443", "https", "/items/1", "b", "/tags/0", "x").
+		Copy("/ports/80", "/items/0")
+	for _, op := range k.Operations() {
+		fmt.Println(op.Op, op.From, op.Path)
+	}
+	replayed := knoa.Replay(knoa.FromJSON([]byte(`{"ports":{"80":"http"},"items":["a"]}`)), k.Operations())
+	fmt.Println(replayed.JSON() == k.JSON(), replayed.Error())
+	// Output:
+	// set  ports."443"
+	// set  items[1]
+	// set  tags[0]
+	// copy ports."80" items[0]
+	// true <nil>
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+const personSchema = `{
+	"type": "object",
+	"required": ["firstname"],
+	"properties": {
+		"firstname": {"type": "string"},
+		"age": {"type": "integer", "minimum": 18},
+		"siblings": {"type": "array", "items": {"$ref": "#"}}
+	}
+}`
+
+func Example_validate() {
+	k := knoa.Map().Set("firstname", "Jane", "age", 20, "siblings", []any{
+		map[string]any{"firstname": "Tim", "age": 21},
+		map[string]any{"age": 16},
+	})
+	err := k.Validate(personSchema)
+	fmt.Println(err)
+	var validationErr *knoa.ValidationError
+	if errors.As(err, &validationErr) {
+		fmt.Println(validationErr.Path, validationErr.Keyword)
+	}
+	// Output:
+	// 'siblings[1].firstname' is required
+	// 'siblings[1].age' must be greater than or equal to 18
+	// siblings[1].firstname required
+}
+
+func Example_withSchema() {
+	k := knoa.Map(knoa.WithSchema(personSchema)).Set("firstname", "Jane", "age", 20)
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+
+	k.Tx(func(tx knoa.Knoa[map[string]any]) error {
+		tx.Set("age", 16)
+		return nil
+	})
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+
+	k.Unset("firstname")
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+
+	k.Set("age", 30)
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+
+	k.Set("firstname", "Tim")
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+	// Output:
+	// {"age":20,"firstname":"Jane"}
+	// <nil>
+	// {"age":20,"firstname":"Jane"}
+	// transaction rolled back: 'age' must be greater than or equal to 18
+	// {"age":20}
+	// transaction rolled back: 'age' must be greater than or equal to 18
+	// 'firstname' is required
+	// {"age":30}
+	// transaction rolled back: 'age' must be greater than or equal to 18
+	// 'firstname' is required
+	// {"age":30,"firstname":"Tim"}
+	// transaction rolled back: 'age' must be greater than or equal to 18
+}
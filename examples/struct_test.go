@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+type Metadata struct {
+	Name   string            `structs:"name"`
+	Labels map[string]string `structs:"labels,omitempty"`
+}
+
+type Container struct {
+	Image string `structs:"image"`
+	Port  *int   `structs:"port,omitempty"`
+}
+
+type Deployment struct {
+	Metadata
+	Replicas   int         `structs:"replicas"`
+	Containers []Container `structs:"containers"`
+	Owner      *Metadata   `structs:"owner,omitempty"`
+}
+
+func Example_fromStruct() {
+	port := 8080
+	deployment := &Deployment{
+		Metadata: Metadata{
+			Name:   "api",
+			Labels: map[string]string{"tier": "backend"},
+		},
+		Replicas: 1,
+		Containers: []Container{
+			{Image: "api:1.0", Port: &port},
+		},
+		Owner: &Metadata{Name: "platform"},
+	}
+	k := knoa.FromStruct(deployment)
+	fmt.Println(k.JSON())
+
+	k.Set("replicas", 3, "containers[0].image", "api:1.1", "labels.env", "prod")
+	var out Deployment
+	k.To(&out)
+	if err := k.Error(); err != nil {
+		panic(err.Error())
+	}
+	fmt.Println(out.Name, out.Labels, out.Replicas, out.Containers[0].Image, *out.Containers[0].Port, out.Owner.Name)
+	// Output:
+	// {"containers":[{"image":"api:1.0","port":8080}],"labels":{"tier":"backend"},"name":"api","owner":{"name":"platform"},"replicas":1}
+	// api map[env:prod tier:backend] 3 api:1.1 8080 platform
+}
+
+type Autoscaling struct {
+	MinReplicas int `structs:"min_replicas"`
+	MaxReplicas int `structs:"max_replicas"`
+}
+
+func Example_structRoundTrip() {
+	k := knoa.FromStruct(Autoscaling{MinReplicas: 1, MaxReplicas: 3}).Set("max_replicas", 5)
+	var out Autoscaling
+	k.To(&out)
+	fmt.Println(k.JSON())
+	fmt.Println(out.MinReplicas, out.MaxReplicas, k.Error())
+	// Output:
+	// {"max_replicas":5,"min_replicas":1}
+	// 1 5 <nil>
+}
+
+func Example_setEmbeddedStruct() {
+	deployment := Deployment{Metadata: Metadata{Name: "api"}, Replicas: 2}
+	k := knoa.Map().Set("deployment", deployment, "history", []Deployment{deployment})
+	fmt.Println(k.JSON())
+	fmt.Println(knoa.FromStruct(deployment).JSON())
+	// Output:
+	// {"deployment":{"containers":[],"name":"api","replicas":2},"history":[{"containers":[],"name":"api","replicas":2}]}
+	// {"containers":[],"name":"api","replicas":2}
+}
+
+type Service struct {
+	Name     string `structs:"name"`
+	Port     int    `mapstructure:"service_port"`
+	Protocol string `structs:"protocol" mapstructure:"proto"`
+}
+
+func Example_toStructTags() {
+	k := knoa.Map().Set("name", "api", "service_port", 8080, "protocol", "TCP", "proto", "UDP")
+	var out Service
+	k.To(&out)
+	fmt.Println(out.Name, out.Port, out.Protocol, k.Error())
+	// Output:
+	// api 8080 TCP <nil>
+}
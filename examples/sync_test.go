@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_sync() {
+	k := knoa.Sync(knoa.Map().Set("requests", 0))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			k.Set(fmt.Sprintf("workers.w%d", i), true)
+			k.Apply("requests", func(n int) int { return n + 1 })
+		}(i)
+	}
+	wg.Wait()
+	fmt.Println(k.GetInt("requests"))
+	fmt.Println(len(k.Out()["workers"].(map[string]any)))
+	// Output:
+	// 10 true
+	// 10
+}
+
+func TestSync_concurrentWritersAndReaders(t *testing.T) {
+	k := knoa.Sync(knoa.Map().Set("counter", 0))
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(4)
+		go func(i int) {
+			defer wg.Done()
+			k.Set(fmt.Sprintf("items[%d].name", i), fmt.Sprintf("item-%d", i), "tags[+]", i)
+		}(i)
+		go func() {
+			defer wg.Done()
+			k.Apply("counter", func(n int) int { return n + 1 })
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = k.Get("items[*].name")
+			_ = k.JSON()
+			_ = k.YAML()
+			_ = k.JSONPatch()
+		}()
+		go func() {
+			defer wg.Done()
+			out := k.Out()
+			out["counter"] = -1
+			var target map[string]any
+			k.To(&target)
+			_ = k.Error()
+		}()
+	}
+	wg.Wait()
+	counter, _ := k.GetInt("counter")
+	assert.Equal(t, 20, counter)
+	tags, _ := k.GetSlice("tags")
+	assert.Len(t, tags, 20)
+	names, _ := k.GetSlice("items[*].name")
+	assert.Len(t, names, 20)
+	assert.NoError(t, k.Error())
+}
+
+func TestSync_concurrentClones(t *testing.T) {
+	base := knoa.Sync(knoa.Map().Set("base", true))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			clone := base.Clone().Set("id", i)
+			id, _ := clone.GetInt("id")
+			assert.Equal(t, i, id)
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			base.Set(fmt.Sprintf("keys.k%d", i), i)
+			_ = base.JSON()
+		}(i)
+	}
+	wg.Wait()
+	assert.False(t, base.Has("id"))
+	keys, _ := base.GetMap("keys")
+	assert.Len(t, keys, 10)
+}
+
+func TestSync_immutableMode(t *testing.T) {
+	base := knoa.Sync(knoa.Map(knoa.WithImmutableMode(true)).Set("version", 1))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			next := base.Set("version", i+2)
+			version, _ := next.GetInt("version")
+			assert.Equal(t, i+2, version)
+		}(i)
+	}
+	wg.Wait()
+	version, _ := base.GetInt("version")
+	assert.Equal(t, 1, version)
+}
+
+func TestSync_mergeItself(t *testing.T) {
+	k := knoa.Sync(knoa.Map().Set("a", 1))
+	k.Merge(k)
+	assert.Equal(t, `{"a":1}`, k.JSON())
+	assert.Same(t, k, knoa.Sync(k))
+}
+
+func TestSync_concurrentTransactions(t *testing.T) {
+	k := knoa.Sync(knoa.Map().Set("a", 0, "b", 0))
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			k.Tx(func(tx knoa.Knoa[map[string]any]) error {
+				tx.Apply("a", func(n int) int { return n + 1 })
+				tx.Apply("b", func(n int) int { return n - 1 })
+				if i%2 == 0 {
+					return fmt.Errorf("rollback %d", i)
+				}
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+	a, _ := k.GetInt("a")
+	b, _ := k.GetInt("b")
+	assert.Equal(t, 10, a)
+	assert.Equal(t, -10, b)
+	assert.Error(t, k.Error())
+}
+
+func TestSync_history(t *testing.T) {
+	k := knoa.Sync(knoa.Map()).Set("base", true).Snapshot("base")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			k.Set(fmt.Sprintf("k%d", i), i).Snapshot(fmt.Sprintf("s%d", i))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = k.History()
+			_ = k.JSON()
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, k.History(), 11)
+	k.Undo().Undo()
+	assert.Len(t, k.Out(), 9)
+	k.Restore("base")
+	assert.Equal(t, `{"base":true}`, k.JSON())
+	assert.NoError(t, k.Error())
+}
+
+func TestSync_replay(t *testing.T) {
+	ops := knoa.Map().Set("a", 1, "b", 2).Operations()
+	k := knoa.Sync(knoa.Map())
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			knoa.Replay(k, ops)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = k.Operations()
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, k.Operations(), 20)
+	assert.Equal(t, `{"a":1,"b":2}`, k.JSON())
+}
+
+func TestSync_schema(t *testing.T) {
+	schema := `{"properties": {"counter": {"type": "integer", "maximum": 5}}}`
+	k := knoa.Sync(knoa.Map(knoa.WithSchema(schema)).Set("counter", 0))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			k.Tx(func(tx knoa.Knoa[map[string]any]) error {
+				tx.Apply("counter", func(n int) int { return n + 1 })
+				return nil
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			_ = k.Validate(schema)
+		}()
+	}
+	wg.Wait()
+	counter, _ := k.GetInt("counter")
+	assert.Equal(t, 5, counter)
+	assert.NoError(t, k.Validate(schema))
+	var validationErr *knoa.ValidationError
+	assert.ErrorAs(t, k.Error(), &validationErr)
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_moveCopyAndRename() {
+	k := knoa.FromJSON([]byte(`{"spec":{"image":"api:1.0"},"items":[{"oldName":"a"},{"oldName":"b"}]}`))
+	k.Move("spec.image", "image").Copy("image", "spec.previousImage").Rename("items[*].oldName", "name")
+	fmt.Println(k.JSON())
+	fmt.Println(k.JSONPatch())
+	// Output:
+	// {"image":"api:1.0","items":[{"name":"a"},{"name":"b"}],"spec":{"previousImage":"api:1.0"}}
+	// [{"op":"remove","path":"/spec/image"},{"op":"add","path":"/image","value":"api:1.0"},{"op":"add","path":"/spec/previousImage","value":"api:1.0"},{"op":"remove","path":"/items/1/oldName"},{"op":"remove","path":"/items/0/oldName"},{"op":"add","path":"/items/0/name","value":"a"},{"op":"add","path":"/items/1/name","value":"b"}]
+}
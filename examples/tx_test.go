@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_transaction() {
+	k := knoa.Map().Set("balance", 100)
+	k.Tx(func(tx knoa.Knoa[map[string]any]) error {
+		tx.Apply("balance", func(balance int) int { return balance - 30 })
+		tx.Set("movements[+]", -30)
+		return nil
+	})
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+	// Output:
+	// {"balance":70,"movements":[-30]}
+	// <nil>
+}
+
+func Example_transactionRollback() {
+	k := knoa.Map().Set("balance", 100)
+
+	k.Tx(func(tx knoa.Knoa[map[string]any]) error {
+		tx.Set("balance", 50, "movements[", -50)
+		return nil
+	})
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+
+	k = knoa.Map().Set("balance", 100)
+	k.Tx(func(tx knoa.Knoa[map[string]any]) error {
+		tx.Set("movements[+]", -200)
+		tx.Apply("balance", func(balance int) (int, error) {
+			if balance < 200 {
+				return balance, errors.New("insufficient balance")
+			}
+			return balance - 200, nil
+		})
+		return nil
+	})
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+
+	k = knoa.Map().Set("balance", 100)
+	k.Tx(func(tx knoa.Knoa[map[string]any]) error {
+		tx.Set("balance", 0)
+		return errors.New("cancelled")
+	})
+	fmt.Println(k.JSON())
+	fmt.Println(k.Error())
+	// Output:
+	// {"balance":100}
+	// transaction rolled back: invalid path 'movements[': missing ']' at column 10
+	// {"balance":100}
+	// transaction rolled back: apply on 'balance' failed: insufficient balance
+	// {"balance":100}
+	// transaction rolled back: cancelled
+}
+
+func Example_transactionImmutable() {
+	base := knoa.Map(knoa.WithImmutableMode(true)).Set("replicas", 1)
+	next := base.Tx(func(tx knoa.Knoa[map[string]any]) error {
+		tx.Set("replicas", 3).Set("image", "api:2.0")
+		return nil
+	})
+	fmt.Println(base.JSON())
+	fmt.Println(next.JSON())
+	// Output:
+	// {"replicas":1}
+	// {"image":"api:2.0","replicas":3}
+}
+
+func Example_transactionHistory() {
+	k := knoa.Map().Set("replicas", 1).Set("image", "api:1.0")
+	k.Tx(func(tx knoa.Knoa[map[string]any]) error {
+		tx.Undo().Set("image", "api:2.0").Snapshot("v2")
+		return nil
+	})
+	fmt.Println(k.JSON())
+	fmt.Println(k.History())
+	k.Undo()
+	fmt.Println(k.JSON())
+	k.Restore("v2")
+	fmt.Println(k.JSON())
+	fmt.Println(k.History())
+	// Output:
+	// {"image":"api:2.0","replicas":1}
+	// [{set [replicas]} {tx [image]}]
+	// {"replicas":1}
+	// {"image":"api:2.0","replicas":1}
+	// [{set [replicas]} {restore []}]
+}
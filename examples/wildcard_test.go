@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa"
+)
+
+func Example_mapWildcardsAndRecursiveDescent() {
+	k := knoa.FromJSON([]byte(`{
+		"services":{"api":{"replicas":1,"auth":{"password":"secret"}},"db":{"replicas":3,"password":"secret"}},
+		"users":[{"name":"John","password":"secret"}]
+	}`))
+	k.Set("services.*.replicas", 2).Unset("..password")
+	fmt.Println(k.JSON())
+	fmt.Println(k.JSONPatch())
+	fmt.Println(k.Get("..name"))
+	// Output:
+	// {"services":{"api":{"auth":{},"replicas":2},"db":{"replicas":2}},"users":[{"name":"John"}]}
+	// [{"op":"replace","path":"/services/api/replicas","value":2},{"op":"replace","path":"/services/db/replicas","value":2},{"op":"remove","path":"/users/0/password"},{"op":"remove","path":"/services/db/password"},{"op":"remove","path":"/services/api/auth/password"}]
+	// [John] true
+}
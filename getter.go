@@ -0,0 +1,105 @@
+package knoa
+
+import (
+	"github.com/ivancorrales/knoa/internal"
+)
+
+func (k *knoa[T]) Get(path any) (any, bool) {
+	value, found, err := k.get(k.materialize(), path)
+	k.record(err)
+	return value, found
+}
+
+// get looks the path up in the given content, which is neither modified nor referenced by the returned value.
+func (k *knoa[T]) get(content T, path any) (any, bool, error) {
+	m, err := k.parser.ParsePath(path)
+	if err != nil {
+		return nil, false, err
+	}
+	if m == nil {
+		return nil, false, nil
+	}
+	values := m.Child().Lookup(content)
+	if m.IsMultiple() {
+		return internal.Normalize(values), len(values) > 0, nil
+	}
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+	return internal.Normalize(values[0]), true, nil
+}
+
+func (k *knoa[T]) Has(path any) bool {
+	_, found := k.Get(path)
+	return found
+}
+
+func (k *knoa[T]) GetString(path any) (string, bool) {
+	return toString(k.Get(path))
+}
+
+func (k *knoa[T]) GetInt(path any) (int, bool) {
+	return toInt(k.Get(path))
+}
+
+func (k *knoa[T]) GetFloat(path any) (float64, bool) {
+	return toFloat(k.Get(path))
+}
+
+func (k *knoa[T]) GetBool(path any) (bool, bool) {
+	return toBool(k.Get(path))
+}
+
+func (k *knoa[T]) GetSlice(path any) ([]any, bool) {
+	return toSlice(k.Get(path))
+}
+
+func (k *knoa[T]) GetMap(path any) (map[string]any, bool) {
+	return toMap(k.Get(path))
+}
+
+func toString(value any, found bool) (string, bool) {
+	if !found {
+		return "", false
+	}
+	str, ok := value.(string)
+	return str, ok
+}
+
+func toInt(value any, found bool) (int, bool) {
+	if !found {
+		return 0, false
+	}
+	return internal.ToInt(value)
+}
+
+func toFloat(value any, found bool) (float64, bool) {
+	if !found {
+		return 0, false
+	}
+	return internal.ToFloat(value)
+}
+
+func toBool(value any, found bool) (bool, bool) {
+	if !found {
+		return false, false
+	}
+	b, ok := value.(bool)
+	return b, ok
+}
+
+func toSlice(value any, found bool) ([]any, bool) {
+	if !found {
+		return nil, false
+	}
+	items, ok := value.([]any)
+	return items, ok
+}
+
+func toMap(value any, found bool) (map[string]any, bool) {
+	if !found {
+		return nil, false
+	}
+	items, ok := value.(map[string]any)
+	return items, ok
+}
@@ -0,0 +1,113 @@
+package knoa
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa/mutator"
+)
+
+// HistoryEntry describes one of the operations applied to the document.
+type HistoryEntry struct {
+	// Operation is the name of the operation, i.e. set, unset or apply, or tx for transactions.
+	Operation string
+	Paths     []string
+}
+
+type step struct {
+	// end is the number of mutators once the step is applied.
+	end int
+	tx  bool
+}
+
+type undoneStep struct {
+	mutators []mutator.Mutator
+	tx       bool
+}
+
+type snapshot[T Type] struct {
+	out T
+}
+
+// Snapshot saves the current state of the document with the given name, so it can be restored later on.
+func (k *knoa[T]) Snapshot(name string) Knoa[T] {
+	out := k.materialize()
+	// the output is shared with the snapshot from now on
+	k.owned = false
+	target := k.target()
+	snapshots := make(map[string]snapshot[T], len(k.snapshots)+1)
+	for n, s := range k.snapshots {
+		snapshots[n] = s
+	}
+	snapshots[name] = snapshot[T]{out: out}
+	target.snapshots = snapshots
+	return target
+}
+
+// Restore brings the document back to the state saved with the given name. Restoring is an
+// operation itself, so it can be undone like any other.
+func (k *knoa[T]) Restore(name string) Knoa[T] {
+	s, found := k.snapshots[name]
+	if !found {
+		return k.push(nil, fmt.Errorf("snapshot '%s' not found", name))
+	}
+	return k.push(mutator.NewOperation().Restore(s.out), nil)
+}
+
+// Undo reverts the last operation. It does nothing when there are no operations left.
+func (k *knoa[T]) Undo() Knoa[T] {
+	if len(k.steps) == 0 {
+		return k
+	}
+	target := k.target()
+	last := len(target.steps) - 1
+	start := 0
+	if last > 0 {
+		start = target.steps[last-1].end
+	}
+	target.undone = append(target.undone, undoneStep{
+		mutators: capped(target.mutators[start:]),
+		tx:       target.steps[last].tx,
+	})
+	target.mutators = target.mutators[:start:start]
+	target.steps = target.steps[:last:last]
+	if target.kept > last {
+		target.kept = last
+	}
+	target.out, target.applied, target.owned = target.content, 0, false
+	return target
+}
+
+// Redo applies again the last undone operation. It does nothing when there are no undone operations.
+func (k *knoa[T]) Redo() Knoa[T] {
+	if len(k.undone) == 0 {
+		return k
+	}
+	target := k.target()
+	last := len(target.undone) - 1
+	u := target.undone[last]
+	target.undone = target.undone[:last:last]
+	target.mutators = append(target.mutators, u.mutators...)
+	target.steps = append(target.steps, step{end: len(target.mutators), tx: u.tx})
+	return target
+}
+
+// History returns the operations that have been applied to the document, from the oldest to the latest.
+func (k *knoa[T]) History() []HistoryEntry {
+	entries := make([]HistoryEntry, len(k.steps))
+	start := 0
+	for i, s := range k.steps {
+		entry := HistoryEntry{}
+		for _, m := range k.mutators[start:s.end] {
+			if entry.Operation == "" {
+				entry.Operation = m.Operation()
+			}
+			entry.Paths = append(entry.Paths, m.Paths()...)
+		}
+		if s.tx {
+			entry.Operation = "tx"
+		}
+		entries[i] = entry
+		start = s.end
+	}
+	return entries
+}
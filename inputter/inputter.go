@@ -0,0 +1,23 @@
+package inputter
+
+import (
+	"fmt"
+)
+
+type Format string
+
+const (
+	JSONFormat Format = "json"
+	YAMLFormat Format = "yaml"
+)
+
+func Unmarshal(format Format, content []byte) (any, error) {
+	switch format {
+	case JSONFormat:
+		return NewJSON().Unmarshal(content)
+	case YAMLFormat:
+		return NewYAML().Unmarshal(content)
+	default:
+		return nil, fmt.Errorf("unsupported format '%s'", format)
+	}
+}
@@ -0,0 +1,23 @@
+package inputter
+
+import "encoding/json"
+
+type JSON struct{}
+
+type JSONOpt func(json *JSON)
+
+func (j *JSON) Unmarshal(content []byte) (any, error) {
+	var out any
+	if err := json.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func NewJSON(opts ...JSONOpt) *JSON {
+	j := &JSON{}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j
+}
@@ -0,0 +1,50 @@
+package inputter
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+type YAML struct{}
+
+type YAMLOpt func(y *YAML)
+
+func (y *YAML) Unmarshal(content []byte) (any, error) {
+	var out any
+	if err := yaml.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+	return stringifyKeys(out), nil
+}
+
+func NewYAML(opts ...YAMLOpt) *YAML {
+	y := &YAML{}
+	for _, opt := range opts {
+		opt(y)
+	}
+	return y
+}
+
+func stringifyKeys(in any) any {
+	switch value := in.(type) {
+	case map[any]any:
+		out := make(map[string]any, len(value))
+		for k, v := range value {
+			out[fmt.Sprint(k)] = stringifyKeys(v)
+		}
+		return out
+	case map[string]any:
+		for k, v := range value {
+			value[k] = stringifyKeys(v)
+		}
+		return value
+	case []any:
+		for i := range value {
+			value[i] = stringifyKeys(value[i])
+		}
+		return value
+	default:
+		return in
+	}
+}
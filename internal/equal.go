@@ -0,0 +1,39 @@
+package internal
+
+import "reflect"
+
+// Equal compares two normalized values, considering numbers of different
+// kinds as equal when they hold the same value (i.e. 1 and 1.0).
+func Equal(a, b any) bool {
+	if x, ok := ToFloat(a); ok {
+		y, isNumber := ToFloat(b)
+		return isNumber && x == y
+	}
+	switch aValue := a.(type) {
+	case map[string]any:
+		bValue, ok := b.(map[string]any)
+		if !ok || len(aValue) != len(bValue) {
+			return false
+		}
+		for k, v := range aValue {
+			w, found := bValue[k]
+			if !found || !Equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bValue, ok := b.([]any)
+		if !ok || len(aValue) != len(bValue) {
+			return false
+		}
+		for i := range aValue {
+			if !Equal(aValue[i], bValue[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
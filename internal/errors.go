@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// PathError reports an invalid path expression and the column where the problem was found.
+type PathError struct {
+	Path   string
+	Column int
+	Reason string
+}
+
+func (e *PathError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("invalid path '%s': %s", e.Path, e.Reason)
+	}
+	return fmt.Sprintf("invalid path '%s': %s at column %d", e.Path, e.Reason, e.Column)
+}
+
+// TypeMismatchError reports a segment of the path that can't be applied to the value found in
+// the document, i.e. an attribute of a string.
+type TypeMismatchError struct {
+	// Path is the location of the value, empty for the whole document.
+	Path     string
+	Segment  string
+	Expected string
+	Actual   string
+}
+
+func (e *TypeMismatchError) Error() string {
+	if e.Segment == "" {
+		return fmt.Sprintf("invalid value%s: expected %s but found %s", at(e.Path), e.Expected, e.Actual)
+	}
+	return fmt.Sprintf("cannot resolve '%s'%s: expected %s but found %s", e.Segment, at(e.Path), e.Expected, e.Actual)
+}
+
+// IndexOutOfRangeError reports an index that doesn't exist in the array.
+type IndexOutOfRangeError struct {
+	// Path is the location of the array, empty for the whole document.
+	Path    string
+	Segment string
+	Index   int
+	Length  int
+}
+
+func (e *IndexOutOfRangeError) Error() string {
+	return fmt.Sprintf("index %d out of range%s: the array has %d items", e.Index, at(e.Path), e.Length)
+}
+
+// ApplyError reports a func passed to Apply that is not valid or that failed.
+type ApplyError struct {
+	Path string
+	Err  error
+}
+
+func (e *ApplyError) Error() string {
+	return fmt.Sprintf("apply on '%s' failed: %v", e.Path, e.Err)
+}
+
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
+// TestFailedError reports a value that doesn't match the one given to a test operation.
+type TestFailedError struct {
+	Path     string
+	Expected any
+	Actual   any
+}
+
+func (e *TestFailedError) Error() string {
+	return fmt.Sprintf("test failed%s: expected '%v' but found '%v'", at(e.Path), e.Expected, e.Actual)
+}
+
+// PatchError reports an operation of a JSON Patch document that is not valid.
+type PatchError struct {
+	Op     string
+	Reason string
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("invalid '%s' operation: %s", e.Op, e.Reason)
+}
+
+func at(path string) string {
+	if path == "" {
+		return ""
+	}
+	return fmt.Sprintf(" at '%s'", path)
+}
+
+// KindOf describes the type of the values of the documents: map, array, string, number, bool or null.
+func KindOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "map"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	}
+	if _, ok := ToFloat(value); ok {
+		return "number"
+	}
+	return reflect.ValueOf(value).Kind().String()
+}
@@ -1,7 +1,9 @@
 package internal
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/fatih/structs"
 )
@@ -16,7 +18,7 @@ func normalize[T Type](input T) any {
 	case reflect.Slice, reflect.Array:
 		itemsLen := value.Len()
 		if itemsLen == 0 {
-			return make([]any, 1)
+			return make([]any, 0)
 		}
 		output := make([]any, itemsLen)
 		for i := 0; i < itemsLen; i++ {
@@ -25,13 +27,17 @@ func normalize[T Type](input T) any {
 		}
 		return output
 	case reflect.Struct:
-		return structs.Map(input)
+		return structToMap(value)
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return normalize(value.Elem().Interface())
 	case reflect.Map:
 		output := make(map[string]any)
-		if in, ok := reflect.ValueOf(input).Interface().(map[string]any); ok {
-			for k, v := range in {
-				output[k] = evalValue(v)
-			}
+		iter := value.MapRange()
+		for iter.Next() {
+			output[fmt.Sprint(iter.Key().Interface())] = evalValue(iter.Value().Interface())
 		}
 		return output
 	}
@@ -40,12 +46,139 @@ func normalize[T Type](input T) any {
 
 func evalValue(in any) (out any) {
 	switch reflect.ValueOf(in).Kind() {
-	case reflect.Slice, reflect.Array, reflect.Map:
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct, reflect.Ptr, reflect.Interface:
 		out = normalize(in)
-	case reflect.Struct:
-		out = normalize(structs.Map(in))
 	default:
 		out = in
 	}
 	return out
 }
+
+// structToMap follows the `structs` tag conventions, and additionally flattens
+// embedded structs into their parent as encoding/json does.
+func structToMap(value reflect.Value) any {
+	output := make(map[string]any)
+	valueType := value.Type()
+	exported := 0
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		name, opts := parseTag(field.Tag.Get(structs.DefaultTagName))
+		if name == "-" {
+			continue
+		}
+		fieldValue := value.Field(i)
+		if field.Anonymous && name == "" {
+			if fieldValue.Kind() == reflect.Ptr {
+				if fieldValue.IsNil() {
+					continue
+				}
+				fieldValue = fieldValue.Elem()
+			}
+			if fieldValue.Kind() == reflect.Struct {
+				embedded, ok := structToMap(fieldValue).(map[string]any)
+				if ok {
+					for k, v := range embedded {
+						output[k] = v
+					}
+					exported++
+					continue
+				}
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		exported++
+		if name == "" {
+			name = field.Name
+		}
+		if opts.has("omitempty") && fieldValue.IsZero() {
+			continue
+		}
+		switch {
+		case opts.has("string"):
+			if stringer, ok := fieldValue.Interface().(fmt.Stringer); ok {
+				output[name] = stringer.String()
+			}
+		case opts.has("omitnested"):
+			output[name] = fieldValue.Interface()
+		case opts.has("flatten"):
+			if nested, ok := evalValue(fieldValue.Interface()).(map[string]any); ok {
+				for k, v := range nested {
+					output[k] = v
+				}
+			}
+		default:
+			output[name] = evalValue(fieldValue.Interface())
+		}
+	}
+	// Structs without exported fields, such as time.Time, are kept as they are.
+	if exported == 0 && value.CanInterface() {
+		return value.Interface()
+	}
+	return output
+}
+
+type tagOptions []string
+
+func (opts tagOptions) has(opt string) bool {
+	for _, o := range opts {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+// StructTags is a mapstructure decode hook that renames the keys of the maps decoded into structs,
+// so the fields are taken by their `structs` tag and, when they don't have one, by their `mapstructure` tag.
+func StructTags(_ reflect.Type, to reflect.Type, data any) (any, error) {
+	input, ok := data.(map[string]any)
+	if !ok || to.Kind() != reflect.Struct {
+		return data, nil
+	}
+	output := make(map[string]any, len(input))
+	for k, v := range input {
+		output[k] = v
+	}
+	renameFields(to, input, output)
+	return output, nil
+}
+
+func renameFields(structType reflect.Type, input, output map[string]any) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name, _ := parseTag(field.Tag.Get(structs.DefaultTagName))
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				renameFields(embedded, input, output)
+				continue
+			}
+		}
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		key, _ := parseTag(field.Tag.Get("mapstructure"))
+		if key == "" || key == "-" {
+			key = field.Name
+		}
+		value, found := input[name]
+		if !found || key == name {
+			continue
+		}
+		delete(output, name)
+		output[key] = value
+	}
+}
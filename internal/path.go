@@ -0,0 +1,23 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var plainAttribute = regexp.MustCompile(`^[A-Za-z_]+[A-Za-z0-9_/-]*$`)
+
+// AttributePath appends an attribute to a path, quoting those names that are not plain identifiers.
+func AttributePath(path, name string) string {
+	if !plainAttribute.MatchString(name) {
+		name = fmt.Sprintf("%q", name)
+	}
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func IndexPath(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
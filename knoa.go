@@ -3,14 +3,17 @@ package knoa
 import (
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 
 	"github.com/mitchellh/mapstructure"
 
+	"github.com/ivancorrales/knoa/inputter"
 	"github.com/ivancorrales/knoa/internal"
 	"github.com/ivancorrales/knoa/mutator"
 	"github.com/ivancorrales/knoa/outputter"
 	"github.com/ivancorrales/knoa/sanitizer"
+	"github.com/ivancorrales/knoa/schema"
 )
 
 func FromMap(content map[string]any, opts ...Opt) Knoa[map[string]any] {
@@ -29,7 +32,69 @@ func Array(opts ...Opt) Knoa[[]any] {
 	return New[[]any](opts...)
 }
 
-func load[T Type](content T, options ...Opt) Knoa[T] {
+func FromJSON(content []byte, opts ...Opt) Knoa[any] {
+	return decode(inputter.JSONFormat, content, opts...)
+}
+
+func FromYAML(content []byte, opts ...Opt) Knoa[any] {
+	return decode(inputter.YAMLFormat, content, opts...)
+}
+
+func FromReader(reader io.Reader, format Format, opts ...Opt) Knoa[any] {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		k := load[any](nil, opts...)
+		k.record(err)
+		return k
+	}
+	return decode(format, content, opts...)
+}
+
+func decode(format Format, content []byte, opts ...Opt) Knoa[any] {
+	value, err := inputter.Unmarshal(format, content)
+	if err == nil {
+		switch value.(type) {
+		case map[string]any, []any:
+		default:
+			err = fmt.Errorf("unsupported root type '%s'", reflect.ValueOf(value).Kind())
+			value = nil
+		}
+	}
+	k := load[any](value, opts...)
+	k.record(err)
+	return k
+}
+
+func FromStruct(content any, opts ...Opt) Knoa[map[string]any] {
+	value, ok := internal.Normalize(content).(map[string]any)
+	if !ok {
+		k := load[map[string]any](nil, opts...)
+		k.record(fmt.Errorf("unsupported struct type '%s'", reflect.ValueOf(content).Kind()))
+		return k
+	}
+	return load[map[string]any](value, opts...)
+}
+
+func load[T Type](content T, options ...Opt) *knoa[T] {
+	b := newBuilder(options...)
+	c, _ := internal.Normalize(content).(T)
+	k := &knoa[T]{
+		strictMode: b.strictMode,
+		panicMode:  b.panicMode,
+		immutable:  b.immutable,
+		parser:     b.parser(),
+		content:    c,
+		out:        c,
+	}
+	if b.schema != nil {
+		var err error
+		k.schema, err = schema.Compile(b.schema)
+		k.record(err)
+	}
+	return k
+}
+
+func newBuilder(options ...Opt) *builder {
 	b := &builder{
 		strictMode:  false,
 		attrNameFmt: mutator.DefAttributeNameFormat,
@@ -37,46 +102,108 @@ func load[T Type](content T, options ...Opt) Knoa[T] {
 	for _, opt := range options {
 		opt(b)
 	}
-	pathRegExp, attrRegExpr := mutator.RegExpsFromAttributeFormat(b.attrNameFmt)
-	c, _ := internal.Normalize(content).(T)
-	return &knoa[T]{
-		strictMode: b.strictMode,
-		parser: &mutator.Parser{
-			Strict:          b.strictMode,
-			RegExp:          pathRegExp,
-			AttributeRegExp: attrRegExpr,
-		},
-		content: c,
+	return b
+}
+
+func (b *builder) parser() *mutator.Parser {
+	return &mutator.Parser{
+		AttributeRegExp: mutator.AttributeRegExpFromFormat(b.attrNameFmt),
+		Syntax:          b.pathSyntax,
 	}
 }
 
+// CompilePath parses the path in advance, so it can be passed to Set, UnsetPaths, Apply and the getters
+// instead of the path expression.
+func CompilePath(expr string, opts ...Opt) (Path, error) {
+	return newBuilder(opts...).parser().Compile(expr)
+}
+
 type Type internal.Type
 
 type Knoa[T Type] interface {
 	Set(pathValueList ...any) Knoa[T]
-	Unset(pathValueList ...string) Knoa[T]
+	Unset(paths ...string) Knoa[T]
+	// UnsetPaths is Unset for the paths compiled with CompilePath.
+	UnsetPaths(paths ...Path) Knoa[T]
 	Apply(args ...any) Knoa[T]
+	ApplyCtx(path any, fn func(ctx NodeContext) any) Knoa[T]
+	Insert(path string, index int, values ...any) Knoa[T]
+	Move(from, to string) Knoa[T]
+	Copy(from, to string) Knoa[T]
+	Rename(path, name string) Knoa[T]
+	Merge(other any, opts ...mutator.MergeOpt) Knoa[T]
+	Patch(patch []byte) Knoa[T]
+	MergePatch(patch map[string]any) Knoa[T]
 	With(opts ...mutator.OperationOpt) func(pathValueList ...any) Knoa[T]
+	Clone() Knoa[T]
+	Tx(fn func(tx Knoa[T]) error) Knoa[T]
+	Snapshot(name string) Knoa[T]
+	Restore(name string) Knoa[T]
+	Undo() Knoa[T]
+	Redo() Knoa[T]
+	History() []HistoryEntry
+	Operations() []Operation
+	Validate(schema any) error
+	Get(path any) (any, bool)
+	Has(path any) bool
+	GetString(path any) (string, bool)
+	GetInt(path any) (int, bool)
+	GetFloat(path any) (float64, bool)
+	GetBool(path any) (bool, bool)
+	GetSlice(path any) ([]any, bool)
+	GetMap(path any) (map[string]any, bool)
 	Out() T
 	YAML(opts ...outputter.YAMLOpt) string
 	JSON(opts ...outputter.JSONOpt) string
+	JSONPatch(opts ...outputter.JSONOpt) string
 	To(output interface{})
 	Error() error
 }
 
 type knoa[T Type] struct {
 	strictMode bool
+	panicMode  bool
+	immutable  bool
 	mutators   []mutator.Mutator
 	parser     *mutator.Parser
 	content    T
 	err        error
+	// failures are the errors found applying the mutators. They are found again, so replaced, when
+	// the mutators are applied from the content again.
+	failures []failure
+	// out caches the content with the first applied mutators already applied. It is only
+	// modified in place when it is owned, this is, not shared with the content or a clone.
+	out     T
+	applied int
+	owned   bool
+	// steps group the mutators by the operation that added them, so they can be undone together.
+	steps     []step
+	undone    []undoneStep
+	snapshots map[string]snapshot[T]
+	// kept is the number of steps, made before the transaction that runs over this document, that
+	// haven't been undone by the transaction.
+	kept      int
+	schema    *schema.Schema
+	validated bool
+	// schemaErr is the result of the last validation against the schema.
+	schemaErr error
+}
+
+type failure struct {
+	// at is the position of the mutator that failed.
+	at  int
+	err error
 }
 
 type Opt func(sanitizer *builder)
 
 type builder struct {
 	strictMode  bool
+	panicMode   bool
 	attrNameFmt string
+	pathSyntax  mutator.PathSyntax
+	immutable   bool
+	schema      any
 }
 
 func WithStrictMode(strict bool) func(builder *builder) {
@@ -85,113 +212,338 @@ func WithStrictMode(strict bool) func(builder *builder) {
 	}
 }
 
+// WithPanicMode makes the document panic with the errors, as soon as they are found, instead of
+// reporting them through Error.
+func WithPanicMode(panicMode bool) func(builder *builder) {
+	return func(builder *builder) {
+		builder.panicMode = panicMode
+	}
+}
+
 func WithAttributeNameFormat(attrNameFmt string) func(builder *builder) {
 	return func(opts *builder) {
 		opts.attrNameFmt = attrNameFmt
 	}
 }
 
+// WithImmutableMode makes every operation return a new document and leave the receiver untouched.
+func WithImmutableMode(immutable bool) func(builder *builder) {
+	return func(opts *builder) {
+		opts.immutable = immutable
+	}
+}
+
+// WithSchema validates the document against the JSON Schema every time the output changes.
+// The errors are reported through Error, or make a transaction roll back.
+func WithSchema(schema any) func(builder *builder) {
+	return func(opts *builder) {
+		opts.schema = schema
+	}
+}
+
+func WithPathSyntax(syntax PathSyntax) func(builder *builder) {
+	return func(opts *builder) {
+		opts.pathSyntax = syntax
+	}
+}
+
 func New[T Type](options ...Opt) Knoa[T] {
 	var content T
 	return load[T](content, options...)
 }
 
+func (k *knoa[T]) push(mutators []mutator.Mutator, err error) Knoa[T] {
+	target := k.target()
+	target.record(err)
+	if len(mutators) > 0 {
+		target.mutators = append(target.mutators, mutators...)
+		target.steps = append(target.steps, step{end: len(target.mutators)})
+		target.undone = nil
+	}
+	return target
+}
+
+func (k *knoa[T]) record(err error) {
+	k.err = errors.Join(k.err, k.check(err))
+}
+
+// fail records the error found applying the mutator at the given position.
+func (k *knoa[T]) fail(at int, err error) {
+	k.failures = append(k.failures, failure{at: at, err: k.check(err)})
+}
+
+// applyErrors joins the errors found applying the mutators from the given position on.
+func (k *knoa[T]) applyErrors(from int) error {
+	var errs []error
+	for _, f := range k.failures {
+		if f.at >= from {
+			errs = append(errs, f.err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// check panics with the error in panic mode, or returns it otherwise.
+func (k *knoa[T]) check(err error) error {
+	if err != nil && k.panicMode {
+		panic(err)
+	}
+	return err
+}
+
+// target is the document an operation modifies: the receiver, or a clone in immutable mode.
+func (k *knoa[T]) target() *knoa[T] {
+	if k.immutable {
+		return k.clone()
+	}
+	return k
+}
+
+func (k *knoa[T]) Clone() Knoa[T] {
+	return k.clone()
+}
+
+// clone shares the content, the output and the mutators with the receiver. The capacity of the
+// slices is limited so that appending to one of them doesn't affect the other.
+func (k *knoa[T]) clone() *knoa[T] {
+	k.owned = false
+	c := *k
+	c.mutators = capped(k.mutators)
+	c.steps = capped(k.steps)
+	c.undone = capped(k.undone)
+	c.failures = capped(k.failures)
+	return &c
+}
+
+func capped[S ~[]E, E any](s S) S {
+	return s[:len(s):len(s)]
+}
+
+// Tx runs fn over a copy of the document, that is committed only when fn returns no error and
+// all the operations made through tx can be parsed and applied, and the result matches the schema
+// if any. Otherwise, the document is left as it was and the error is recorded.
+func (k *knoa[T]) Tx(fn func(tx Knoa[T]) error) Knoa[T] {
+	k.applyPending()
+	tx := k.clone()
+	// the result of the transaction is validated even if it doesn't change the document
+	tx.immutable, tx.panicMode, tx.err, tx.validated, tx.kept = false, false, nil, false, len(k.steps)
+	err := fn(tx)
+	tx.materialize()
+	// the mutators of the steps kept were already applied before the transaction
+	steps := capped(tx.steps[:tx.kept])
+	start := 0
+	if tx.kept > 0 {
+		start = steps[tx.kept-1].end
+	}
+	if err = errors.Join(err, tx.err, tx.applyErrors(start), tx.schemaErr); err != nil {
+		return k.push(nil, fmt.Errorf("transaction rolled back: %w", err))
+	}
+	// the operations made through tx, undo and redo included, are a single step
+	if len(tx.mutators) > start {
+		steps = append(steps, step{end: len(tx.mutators), tx: true})
+	}
+	target := k.target()
+	target.mutators, target.out, target.applied, target.owned = tx.mutators, tx.out, tx.applied, tx.owned
+	target.steps, target.undone, target.snapshots, target.failures = steps, tx.undone, tx.snapshots, tx.failures
+	target.validated, target.schemaErr = tx.validated, tx.schemaErr
+	if target.kept > tx.kept {
+		// the transaction runs inside another one, whose steps have been undone
+		target.kept = tx.kept
+	}
+	return target
+}
+
 func (k *knoa[T]) With(opts ...mutator.OperationOpt) func(args ...any) Knoa[T] {
 	setter := mutator.NewOperation(opts...)
 	return func(args ...any) Knoa[T] {
-		pathValueList := sanitizer.SanitizePathValueList(k.strictMode, args...)
-		mutators, err := setter.Set(k.parser, pathValueList)
-		if err != nil {
-			k.err = errors.Join(k.err, err)
-		}
-		k.mutators = append(k.mutators, mutators...)
-		return k
+		pathValueList, err := sanitizer.SanitizePathValueList(k.strictMode, args...)
+		mutators, setErr := setter.Set(k.parser, pathValueList)
+		return k.push(mutators, errors.Join(err, setErr))
 	}
 }
 
 func (k *knoa[T]) Set(args ...any) Knoa[T] {
-	pathValueList := sanitizer.SanitizePathValueList(k.strictMode, args...)
-	mutators, err := mutator.NewOperation().Set(k.parser, pathValueList)
-	if err != nil {
-		k.err = errors.Join(k.err, err)
-	}
-	k.mutators = append(k.mutators, mutators...)
-	return k
+	pathValueList, err := sanitizer.SanitizePathValueList(k.strictMode, args...)
+	mutators, setErr := mutator.NewOperation().Set(k.parser, pathValueList)
+	return k.push(mutators, errors.Join(err, setErr))
 }
 
 func (k *knoa[T]) Unset(args ...string) Knoa[T] {
-	mutators, err := mutator.NewOperation().Unset(k.parser, args)
-	if err != nil {
-		k.err = errors.Join(k.err, err)
+	paths := make([]sanitizer.Path, len(args))
+	for i := range args {
+		paths[i] = args[i]
 	}
-	k.mutators = append(k.mutators, mutators...)
-	return k
+	return k.push(mutator.NewOperation().Unset(k.parser, paths))
+}
+
+func (k *knoa[T]) UnsetPaths(args ...Path) Knoa[T] {
+	paths := make([]sanitizer.Path, len(args))
+	for i := range args {
+		paths[i] = args[i]
+	}
+	return k.push(mutator.NewOperation().Unset(k.parser, paths))
 }
 
 func (k *knoa[T]) Apply(args ...any) Knoa[T] {
-	pathFuncList := sanitizer.SanitizePathFuncList(k.strictMode, args...)
-	mutators, err := mutator.NewOperation().Apply(k.parser, pathFuncList)
+	pathFuncList, err := sanitizer.SanitizePathFuncList(k.strictMode, args...)
+	mutators, applyErr := mutator.NewOperation().Apply(k.parser, pathFuncList)
+	return k.push(mutators, errors.Join(err, applyErr))
+}
+
+func (k *knoa[T]) ApplyCtx(path any, fn func(ctx NodeContext) any) Knoa[T] {
+	return k.push(mutator.NewOperation().ApplyCtx(k.parser, path, fn))
+}
+
+func (k *knoa[T]) Insert(path string, index int, values ...any) Knoa[T] {
+	return k.push(mutator.NewOperation().Insert(k.parser, path, index, values))
+}
+
+func (k *knoa[T]) Move(from, to string) Knoa[T] {
+	return k.push(mutator.NewOperation().Move(k.parser, from, to))
+}
+
+func (k *knoa[T]) Copy(from, to string) Knoa[T] {
+	return k.push(mutator.NewOperation().Copy(k.parser, from, to))
+}
+
+func (k *knoa[T]) Rename(path, name string) Knoa[T] {
+	return k.push(mutator.NewOperation().Rename(k.parser, path, name))
+}
+
+func (k *knoa[T]) Merge(other any, opts ...mutator.MergeOpt) Knoa[T] {
+	return k.push(mutator.NewOperation().Merge(mergeContent(other), opts...), nil)
+}
+
+func mergeContent(other any) any {
+	switch o := other.(type) {
+	case Knoa[map[string]any]:
+		return o.Out()
+	case Knoa[[]any]:
+		return o.Out()
+	case Knoa[any]:
+		return o.Out()
+	}
+	return other
+}
+
+func (k *knoa[T]) Patch(patch []byte) Knoa[T] {
+	ops, err := mutator.ParsePatch(patch)
 	if err != nil {
-		k.err = errors.Join(k.err, err)
+		return k.push(nil, err)
 	}
-	k.mutators = append(k.mutators, mutators...)
-	return k
+	return k.push(mutator.NewOperation().Patch(ops))
+}
+
+func (k *knoa[T]) MergePatch(patch map[string]any) Knoa[T] {
+	return k.push(mutator.NewOperation().MergePatch(patch), nil)
 }
 
 func (k *knoa[T]) Out() T {
-	var content T = k.content
-	for _, m := range k.mutators {
-		switch reflect.ValueOf(content).Kind() {
-		case reflect.Slice, reflect.Array:
-			in, ok := reflect.ValueOf(content).Interface().([]any)
-			if !ok {
-				k.err = fmt.Errorf("unsupported array type")
-				break
-			}
-			arrayIn, err := m.Child().ToArray(in)
-			if err != nil {
-				k.err = errors.Join(k.err, err)
-				break
-			}
-			content, _ = reflect.ValueOf(arrayIn).Interface().(T)
-		case reflect.Map:
-			in, ok := reflect.ValueOf(content).Interface().(map[string]any)
-			if !ok {
-				k.err = errors.Join(k.err, fmt.Errorf("unsupported map type"))
-				break
-			}
-			mapIn, err := m.Child().ToMap(in)
-			if err != nil {
-				k.err = errors.Join(k.err, err)
-				break
-			}
-			content, _ = reflect.ValueOf(mapIn).Interface().(T)
-		default:
-			k.err = errors.Join(k.err, fmt.Errorf("unsupporteed output type '%s'", reflect.TypeOf(content).Kind()))
+	out, _ := internal.Normalize(k.materialize()).(T)
+	return out
+}
+
+// materialize applies the pending mutators and returns the cached output, that must not be modified.
+// The output is validated against the schema, if any, whenever it changes.
+func (k *knoa[T]) materialize() T {
+	if !k.pending() {
+		return k.out
+	}
+	k.applyPending()
+	if k.schema != nil {
+		k.validated = true
+		k.schemaErr = k.check(k.schema.Validate(k.out))
+	}
+	return k.out
+}
+
+func (k *knoa[T]) pending() bool {
+	return k.applied < len(k.mutators) || (k.schema != nil && !k.validated)
+}
+
+func (k *knoa[T]) applyPending() {
+	if k.applied == len(k.mutators) {
+		return
+	}
+	content := k.out
+	if !k.owned {
+		content, _ = internal.Normalize(k.out).(T)
+	}
+	if k.applied == 0 {
+		k.failures = nil
+	}
+	for i, m := range k.mutators[k.applied:] {
+		out, err := mutator.Mutate(content, m)
+		if err != nil {
+			k.fail(k.applied+i, err)
+			continue
+		}
+		value, ok := out.(T)
+		if !ok {
+			k.fail(k.applied+i, fmt.Errorf("unsupported output type '%s'", reflect.ValueOf(out).Kind()))
+			continue
 		}
+		content = value
 	}
-	return content
+	k.out, k.applied, k.owned, k.validated = content, len(k.mutators), true, false
+}
+
+// Validate checks the document against a JSON Schema, given as a map, its JSON representation or a
+// compiled schema, and returns the errors found with the path of the values that are not valid.
+func (k *knoa[T]) Validate(s any) error {
+	return validate(s, k.materialize())
+}
+
+func validate(s, content any) error {
+	compiled, err := schema.Compile(s)
+	if err != nil {
+		return err
+	}
+	return compiled.Validate(content)
 }
 
 func (k *knoa[T]) YAML(opts ...outputter.YAMLOpt) string {
-	content := k.Out()
-	str, err := outputter.NewYAML(opts...).Marshal(content)
-	k.err = errors.Join(k.err, err)
+	str, err := outputter.NewYAML(opts...).Marshal(k.materialize())
+	k.record(err)
 	return str
 }
 
 func (k *knoa[T]) JSON(opts ...outputter.JSONOpt) string {
-	content := k.Out()
-	str, err := outputter.NewJSON(opts...).Marshal(content)
-	k.err = errors.Join(k.err, err)
+	str, err := outputter.NewJSON(opts...).Marshal(k.materialize())
+	k.record(err)
 	return str
 }
 
+func (k *knoa[T]) JSONPatch(opts ...outputter.JSONOpt) string {
+	str, err := k.jsonPatch(opts...)
+	k.record(err)
+	return str
+}
+
+func (k *knoa[T]) jsonPatch(opts ...outputter.JSONOpt) (string, error) {
+	patch, patchErr := mutator.NewPatch(k.content, k.mutators)
+	str, err := outputter.NewJSON(opts...).Marshal(patch)
+	return str, errors.Join(patchErr, err)
+}
+
 func (k *knoa[T]) To(out interface{}) {
-	content := k.Out()
-	k.err = errors.Join(k.err, mapstructure.Decode(content, out))
+	k.record(decodeTo(k.Out(), out))
+}
+
+func decodeTo(content any, out interface{}) error {
+	// the fields are decoded with the same tags used to build the documents from structs,
+	// and the `mapstructure` tags are still taken by the fields without them
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Squash:     true,
+		DecodeHook: internal.StructTags,
+		Result:     out,
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(content)
 }
 
 func (k *knoa[T]) Error() error {
-	return k.err
+	return errors.Join(k.err, k.applyErrors(0), k.schemaErr)
 }
@@ -0,0 +1,187 @@
+package mutator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/ivancorrales/knoa/internal"
+)
+
+var (
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
+	stringType = reflect.TypeOf("")
+)
+
+// applyFunc wraps the functions passed to Apply: func(T) R, func(T) (R, error) and func(path string, T) R.
+type applyFunc struct {
+	fn reflect.Value
+}
+
+func newApplyFunc(fn reflect.Value) (*applyFunc, error) {
+	if fn.Kind() != reflect.Func || fn.IsNil() {
+		return nil, fmt.Errorf("invalid func '%v'", fn)
+	}
+	t := fn.Type()
+	switch {
+	case t.IsVariadic():
+		return nil, fmt.Errorf("unsupported func '%s': variadic funcs are not supported", t)
+	case t.NumIn() == 0 || t.NumIn() > 2:
+		return nil, fmt.Errorf("unsupported func '%s': it must receive the value and optionally the path", t)
+	case t.NumIn() == 2 && t.In(0) != stringType:
+		return nil, fmt.Errorf("unsupported func '%s': the path must be a string", t)
+	case t.NumOut() == 0 || t.NumOut() > 2:
+		return nil, fmt.Errorf("unsupported func '%s': it must return a value and optionally an error", t)
+	case t.NumOut() == 2 && !t.Out(1).Implements(errorType):
+		return nil, fmt.Errorf("unsupported func '%s': the second returned value must be an error", t)
+	}
+	return &applyFunc{fn: fn}, nil
+}
+
+func (f *applyFunc) call(path string, in any) (any, error) {
+	t := f.fn.Type()
+	arg, err := convertArg(in, t.In(t.NumIn()-1))
+	if err != nil {
+		return nil, err
+	}
+	args := []reflect.Value{arg}
+	if t.NumIn() == 2 {
+		args = []reflect.Value{reflect.ValueOf(path), arg}
+	}
+	out := f.fn.Call(args)
+	if len(out) == 2 && !out[1].IsNil() {
+		return nil, out[1].Interface().(error)
+	}
+	return internal.Normalize(out[0].Interface()), nil
+}
+
+// convertArg turns the value of the document into the type the func expects. Numbers are converted
+// between kinds as long as no precision is lost, and the rest of values are decoded when possible.
+func convertArg(in any, t reflect.Type) (reflect.Value, error) {
+	if in == nil {
+		return reflect.Zero(t), nil
+	}
+	v := reflect.ValueOf(in)
+	if v.Type().AssignableTo(t) {
+		return v, nil
+	}
+	if isNumberKind(v.Kind()) && isNumberKind(t.Kind()) {
+		return convertNumber(v, t)
+	}
+	if v.Kind() == t.Kind() && v.Type().ConvertibleTo(t) {
+		return v.Convert(t), nil
+	}
+	out := reflect.New(t)
+	if err := mapstructure.Decode(in, out.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("cannot use '%v' as '%s'", in, t)
+	}
+	return out.Elem(), nil
+}
+
+func convertNumber(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	out := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f, _ := internal.ToFloat(v.Interface())
+		out.SetFloat(f)
+		return out, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := internal.ToInt(v.Interface())
+		if !ok || out.OverflowInt(int64(i)) {
+			return reflect.Value{}, fmt.Errorf("cannot use '%v' as '%s'", v.Interface(), t)
+		}
+		out.SetInt(int64(i))
+		return out, nil
+	default:
+		i, ok := internal.ToInt(v.Interface())
+		if !ok || i < 0 || out.OverflowUint(uint64(i)) {
+			return reflect.Value{}, fmt.Errorf("cannot use '%v' as '%s'", v.Interface(), t)
+		}
+		out.SetUint(uint64(i))
+		return out, nil
+	}
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// apply calls the func of an Apply operation with the value in the given path.
+func (m *Mutator) apply(path string, in any) (any, error) {
+	f, ok := m.value.(*applyFunc)
+	if !ok {
+		return in, fmt.Errorf("invalid apply value")
+	}
+	out, err := f.call(path, in)
+	if err != nil {
+		return in, &ApplyError{Path: path, Err: err}
+	}
+	return out, nil
+}
+
+// NodeContext describes the node an ApplyCtx func is called for.
+type NodeContext struct {
+	// Path is the concrete path of the node, i.e. items[2].total.
+	Path string
+	// Indexes are the array indexes, or map keys, taken by the wildcards of the path.
+	Indexes []string
+	// Value is the current value of the node, or nil when it doesn't exist yet.
+	Value any
+	// Parent is a copy of the map or array that contains the node.
+	Parent any
+	root   any
+}
+
+// Root returns a copy of the whole document as it was before the operation.
+func (c NodeContext) Root() any {
+	return internal.Normalize(c.root)
+}
+
+// location is where a node is found within the document.
+type location struct {
+	path    string
+	indexes []string
+	root    any
+}
+
+func (l location) attribute(name string) location {
+	return location{path: internal.AttributePath(l.path, name), indexes: l.indexes, root: l.root}
+}
+
+func (l location) item(index int, wildcard bool) location {
+	out := location{path: internal.IndexPath(l.path, index), indexes: l.indexes, root: l.root}
+	if wildcard {
+		out.indexes = append(append([]string{}, l.indexes...), strconv.Itoa(index))
+	}
+	return out
+}
+
+func (m *Mutator) attributeLocation() location {
+	return m.loc.attribute(m.name)
+}
+
+func (m *Mutator) itemLocation(index int) location {
+	return m.loc.item(index, m.isRange())
+}
+
+func (m *Mutator) applyCtx(loc location, parent, in any) any {
+	fn, ok := m.value.(func(ctx NodeContext) any)
+	if !ok {
+		return in
+	}
+	return internal.Normalize(fn(NodeContext{
+		Path:    loc.path,
+		Indexes: loc.indexes,
+		Value:   internal.Normalize(in),
+		Parent:  internal.Normalize(parent),
+		root:    loc.root,
+	}))
+}
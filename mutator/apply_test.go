@@ -0,0 +1,125 @@
+package mutator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivancorrales/knoa/internal"
+	"github.com/ivancorrales/knoa/sanitizer"
+)
+
+func Test_newApplyFunc(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      any
+		wantErr bool
+	}{
+		{name: "Value to value", fn: func(int) int { return 0 }},
+		{name: "Value to value and error", fn: func(string) (bool, error) { return false, nil }},
+		{name: "Path and value to value", fn: func(string, float64) any { return nil }},
+		{name: "No arguments", fn: func() int { return 0 }, wantErr: true},
+		{name: "Path is not a string", fn: func(int, int) int { return 0 }, wantErr: true},
+		{name: "No returned values", fn: func(int) {}, wantErr: true},
+		{name: "Second returned value is not an error", fn: func(int) (int, int) { return 0, 0 }, wantErr: true},
+		{name: "Variadic", fn: func(...int) int { return 0 }, wantErr: true},
+		{name: "Not a func", fn: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newApplyFunc(reflect.ValueOf(tt.fn))
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
+
+func Test_applyFunc_call(t *testing.T) {
+	type Name string
+	tests := []struct {
+		name    string
+		fn      any
+		path    string
+		in      any
+		want    any
+		wantErr bool
+	}{
+		{name: "Integral floats are passed as ints", fn: func(i int) int { return i * 2 }, in: float64(21), want: 42},
+		{name: "Non integral floats are not passed as ints", fn: func(i int) int { return i }, in: 1.5, wantErr: true},
+		{name: "Ints are passed as floats", fn: func(f float64) float64 { return f / 2 }, in: 3, want: 1.5},
+		{name: "Overflow", fn: func(i int8) int8 { return i }, in: 300, wantErr: true},
+		{name: "Negative numbers are not passed as unsigned", fn: func(u uint) uint { return u }, in: -1, wantErr: true},
+		{name: "Named types", fn: func(n Name) string { return strings.ToUpper(string(n)) }, in: "jane", want: "JANE"},
+		{name: "Null values are passed as zero values", fn: func(i int) int { return i + 1 }, in: nil, want: 1},
+		{name: "Typed slices", fn: func(s []string) string { return strings.Join(s, ",") }, in: []any{"a", "b"}, want: "a,b"},
+		{name: "Results are normalized", fn: func(s string) []string { return []string{s} }, in: "a", want: []any{"a"}},
+		{name: "Unexpected types", fn: func(i int) int { return i }, in: "a", wantErr: true},
+		{name: "Returned errors", fn: func(string) (string, error) { return "", errors.New("boom") }, in: "a", wantErr: true},
+		{name: "The path is passed", fn: func(path string, v any) string { return path }, path: "items[0]", in: "a", want: "items[0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newApplyFunc(reflect.ValueOf(tt.fn))
+			assert.NoError(t, err)
+			got, err := f.call(tt.path, tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_mutator_applyMissing(t *testing.T) {
+	parser := &Parser{}
+	content := map[string]any{"replicas": 1, "ports": []any{80}, "owner": nil}
+	for _, pathExpr := range []string{"missing", "missing.replicas", "ports[3]", "ports[+]", "owner.name"} {
+		mutators, err := NewOperation().Apply(parser, sanitizer.PathFuncList{
+			{Path: pathExpr, Func: reflect.ValueOf(func(i int) int { return i + 1 })},
+		})
+		assert.NoError(t, err)
+		got, err := Mutate(internal.Normalize(content), mutators[0])
+		assert.NoError(t, err, pathExpr)
+		assert.Equal(t, content, got, pathExpr)
+	}
+}
+
+func Test_mutator_applyCtx(t *testing.T) {
+	_, attrRegExp := RegExpsFromAttributeFormat(DefAttributeNameFormat)
+	p := &Parser{AttributeRegExp: attrRegExp}
+	content := func() map[string]any {
+		return map[string]any{
+			"groups": map[string]any{
+				"a": map[string]any{"items": []any{map[string]any{"qty": 2}}},
+				"b": map[string]any{"items": []any{map[string]any{"qty": 3}, map[string]any{"qty": 4}}},
+			},
+		}
+	}
+	var contexts []NodeContext
+	mutators, err := NewOperation().ApplyCtx(p, "groups.*.items[*].seen", func(ctx NodeContext) any {
+		contexts = append(contexts, ctx)
+		return ctx.Parent.(map[string]any)["qty"]
+	})
+	assert.NoError(t, err)
+	got, err := Mutate(content(), mutators[0])
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"groups": map[string]any{
+			"a": map[string]any{"items": []any{map[string]any{"qty": 2, "seen": 2}}},
+			"b": map[string]any{"items": []any{map[string]any{"qty": 3, "seen": 3}, map[string]any{"qty": 4, "seen": 4}}},
+		},
+	}, got)
+	assert.Len(t, contexts, 3)
+	assert.Equal(t, "groups.b.items[1].seen", contexts[2].Path)
+	assert.Equal(t, []string{"b", "1"}, contexts[2].Indexes)
+	assert.Nil(t, contexts[2].Value)
+	assert.Equal(t, content(), contexts[2].Root(), "the root is the document before the operation")
+
+	_, err = NewOperation().ApplyCtx(p, "groups", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+package mutator
+
+import (
+	"container/list"
+	"sync"
+)
+
+const defaultPathCacheSize = 512
+
+type cacheEntry struct {
+	expr string
+	root *Mutator
+}
+
+// pathCache keeps the most recently parsed paths. The cached mutators are never modified.
+type pathCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func newPathCache(size int) *pathCache {
+	return &pathCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *pathCache) get(expr string) (*Mutator, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, found := c.entries[expr]
+	if !found {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*cacheEntry).root, true
+}
+
+func (c *pathCache) add(expr string, root *Mutator) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, found := c.entries[expr]; found {
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[expr] = c.order.PushFront(&cacheEntry{expr: expr, root: root})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).expr)
+	}
+}
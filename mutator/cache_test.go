@@ -0,0 +1,55 @@
+package mutator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pathCache(t *testing.T) {
+	cache := newPathCache(2)
+	cache.add("a", &Mutator{name: "a"})
+	cache.add("b", &Mutator{name: "b"})
+	_, found := cache.get("a")
+	assert.True(t, found)
+	cache.add("c", &Mutator{name: "c"})
+
+	_, found = cache.get("b")
+	assert.False(t, found, "the least recently used path is evicted")
+	a, found := cache.get("a")
+	assert.True(t, found)
+	assert.Equal(t, "a", a.name)
+	_, found = cache.get("c")
+	assert.True(t, found)
+}
+
+func Test_parser_compile(t *testing.T) {
+	_, attrRegExp := RegExpsFromAttributeFormat(DefAttributeNameFormat)
+	p := &Parser{AttributeRegExp: attrRegExp}
+	path, err := p.Compile("spec.containers[0].image")
+	assert.NoError(t, err)
+	assert.Equal(t, "spec.containers[0].image", path.String())
+
+	first, err := p.ParsePath(path)
+	assert.NoError(t, err)
+	first.addValueToNode("nginx")
+	first.operation = unsetOp
+	second, err := p.ParsePath(path)
+	assert.NoError(t, err)
+	assert.Nil(t, second.child.child.child.child.value, "compiled paths are not modified by their uses")
+	assert.Equal(t, setOp, second.operation)
+
+	parsed, err := p.Parse("spec.containers[0].image")
+	assert.NoError(t, err)
+	assert.Equal(t, second, parsed)
+
+	_, err = p.Compile("spec.")
+	assert.Error(t, err)
+	_, err = p.ParsePath(Path{})
+	assert.Error(t, err)
+	_, err = p.ParsePath(3)
+	assert.Error(t, err)
+	_, err = p.ParsePath(time.Second)
+	assert.EqualError(t, err, "invalid path '1s': paths must be strings")
+}
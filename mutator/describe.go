@@ -0,0 +1,60 @@
+package mutator
+
+var operationNames = map[operationCode]string{
+	setOp:      "set",
+	unsetOp:    "unset",
+	applyOp:    "apply",
+	mergeOp:    "merge",
+	insertOp:   "insert",
+	replaceOp:  "replace",
+	testOp:     "test",
+	moveOp:     "move",
+	copyOp:     "copy",
+	patchOp:    "patch",
+	applyCtxOp: "applyCtx",
+	restoreOp:  "restore",
+}
+
+// Operation returns the name of the operation the mutator performs.
+func (m *Mutator) Operation() string {
+	return operationNames[m.operation]
+}
+
+// Path renders the path expression of the nodes below the mutator.
+func (m *Mutator) Path() string {
+	path := ""
+	for n := m.child; n != nil; n = n.child {
+		switch {
+		case n.index == "" && n.name == "" && !n.literal:
+		case path == "" || n.index != "" || n.recursive:
+			path += n.segment()
+		default:
+			path += "." + n.segment()
+		}
+	}
+	return path
+}
+
+// Paths returns the paths the mutator is applied to. Both the source and the target are
+// returned for moves and copies, and none for merges and restores.
+func (m *Mutator) Paths() []string {
+	if m.child == nil || m.operation == restoreOp {
+		return nil
+	}
+	switch v := m.child.value.(type) {
+	case *mergeValue:
+		return nil
+	case *transferValue:
+		if v.op.Op != "" {
+			return []string{v.op.From, v.op.Path}
+		}
+		return []string{v.fromPath, v.toPath}
+	case *patchValue:
+		paths := make([]string, len(v.patch))
+		for i, o := range v.patch {
+			paths[i] = o.Path
+		}
+		return paths
+	}
+	return []string{m.Path()}
+}
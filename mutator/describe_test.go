@@ -0,0 +1,62 @@
+package mutator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivancorrales/knoa/sanitizer"
+)
+
+func Test_mutator_path(t *testing.T) {
+	tests := []struct {
+		name     string
+		pathExpr string
+		want     string
+	}{
+		{name: "attributes", pathExpr: "a.b.c", want: "a.b.c"},
+		{name: "indexes", pathExpr: "items[0].tags[+]", want: "items[0].tags[+]"},
+		{name: "ranges", pathExpr: "items[1:-1].tags[?(@ == 'x')]", want: "items[1:-1].tags[?(@ == 'x')]"},
+		{name: "root array", pathExpr: "[2].name", want: "[2].name"},
+		{name: "wildcards", pathExpr: "services.*..password", want: "services.*..password"},
+		{name: "quoted keys", pathExpr: `labels["app.kubernetes.io/name"].'*'`, want: `labels."app.kubernetes.io/name"."*"`},
+	}
+	parser := &Parser{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := parser.Parse(tt.pathExpr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, m.Path())
+			again, err := parser.Parse(m.Path())
+			assert.NoError(t, err)
+			assert.Equal(t, m, again)
+		})
+	}
+}
+
+func Test_mutator_paths(t *testing.T) {
+	parser := &Parser{}
+	op := NewOperation()
+	set, _ := op.Set(parser, sanitizer.PathValueList{{Path: "a.b", Value: 1}})
+	unset, _ := op.Unset(parser, []sanitizer.Path{"a"})
+	move, _ := op.Move(parser, "a", "b")
+	patch, _ := op.Patch(Patch{{Op: PatchAdd, Path: "/a", Value: 1}, {Op: PatchRemove, Path: "/b"}})
+	tests := []struct {
+		name      string
+		mutator   Mutator
+		operation string
+		want      []string
+	}{
+		{name: "set", mutator: set[0], operation: "set", want: []string{"a.b"}},
+		{name: "unset", mutator: unset[0], operation: "unset", want: []string{"a"}},
+		{name: "move", mutator: move[0], operation: "move", want: []string{"a", "b"}},
+		{name: "patch", mutator: patch[0], operation: "patch", want: []string{"/a", "/b"}},
+		{name: "merge", mutator: op.Merge(map[string]any{})[0], operation: "merge"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.operation, tt.mutator.Operation())
+			assert.Equal(t, tt.want, tt.mutator.Paths())
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package mutator
+
+import (
+	"github.com/ivancorrales/knoa/internal"
+)
+
+type (
+	PathError            = internal.PathError
+	TypeMismatchError    = internal.TypeMismatchError
+	IndexOutOfRangeError = internal.IndexOutOfRangeError
+	ApplyError           = internal.ApplyError
+	TestFailedError      = internal.TestFailedError
+	PatchError           = internal.PatchError
+)
+
+// segment renders the node as it's written in a path expression.
+func (m *Mutator) segment() string {
+	if m.index != "" {
+		return "[" + m.index + "]"
+	}
+	name := m.name
+	if !m.isWildcard() {
+		name = internal.AttributePath("", name)
+	}
+	if m.recursive {
+		return recursiveSeparator + name
+	}
+	return name
+}
+
+func (m *Mutator) typeMismatch(expected string, actual any) error {
+	segment := ""
+	if !m.isSelf() {
+		segment = m.segment()
+	}
+	return &TypeMismatchError{Path: m.loc.path, Segment: segment, Expected: expected, Actual: internal.KindOf(actual)}
+}
+
+// notFound reports a path that doesn't exist in the document.
+func notFound(path string) error {
+	return &PathError{Path: path, Reason: "not found in the document"}
+}
+
+// testFailed reports a value that doesn't match the one given to a test operation.
+func testFailed(path string, expected, actual any) error {
+	return &TestFailedError{Path: path, Expected: expected, Actual: actual}
+}
+
+func (m *Mutator) indexOutOfRange(index, length int) error {
+	return &IndexOutOfRangeError{Path: m.loc.path, Segment: m.segment(), Index: index, Length: length}
+}
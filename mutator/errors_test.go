@@ -0,0 +1,124 @@
+package mutator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivancorrales/knoa/sanitizer"
+)
+
+func Test_mutator_typedErrors(t *testing.T) {
+	content := func() map[string]any {
+		return map[string]any{
+			"name":  "api",
+			"ports": []any{80},
+			"spec":  map[string]any{"replicas": 1},
+		}
+	}
+	tests := []struct {
+		name     string
+		pathExpr string
+		want     error
+	}{
+		{
+			name:     "Attribute of a string",
+			pathExpr: "name.first",
+			want:     &TypeMismatchError{Path: "name", Segment: "first", Expected: "map", Actual: "string"},
+		},
+		{
+			name:     "Index of a number",
+			pathExpr: "spec.replicas[0]",
+			want:     &TypeMismatchError{Path: "spec.replicas", Segment: "[0]", Expected: "array", Actual: "number"},
+		},
+		{
+			name:     "Index of a map",
+			pathExpr: "spec[1]",
+			want:     &TypeMismatchError{Path: "spec", Segment: "[1]", Expected: "array", Actual: "map"},
+		},
+		{
+			name:     "Index of the whole document",
+			pathExpr: "[0]",
+			want:     &TypeMismatchError{Segment: "[0]", Expected: "array", Actual: "map"},
+		},
+		{
+			name:     "Negative index out of range",
+			pathExpr: "ports[-2]",
+			want:     &IndexOutOfRangeError{Path: "ports", Segment: "[-2]", Index: -2, Length: 1},
+		},
+	}
+	parser := &Parser{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutators, err := NewOperation().Set(parser, sanitizer.PathValueList{{Path: tt.pathExpr, Value: 1}})
+			assert.NoError(t, err)
+			_, err = Mutate(content(), mutators[0])
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
+
+func Test_mutator_attributesOfArrays(t *testing.T) {
+	parser := &Parser{}
+	for _, pathExpr := range []string{"name", "name.*", "*"} {
+		mutators, err := NewOperation().Set(parser, sanitizer.PathValueList{{Path: pathExpr, Value: 1}})
+		assert.NoError(t, err)
+		_, err = Mutate([]any{80}, mutators[0])
+		var typeErr *TypeMismatchError
+		assert.ErrorAs(t, err, &typeErr, pathExpr)
+		assert.Equal(t, "map", typeErr.Expected)
+		assert.Equal(t, "array", typeErr.Actual)
+	}
+}
+
+func Test_mutator_pathErrors(t *testing.T) {
+	parser := &Parser{}
+	op := NewOperation()
+	content := map[string]any{"name": "api", "ports": []any{80}}
+
+	_, err := op.Rename(parser, "ports[0]", "port")
+	assert.Equal(t, &PathError{Path: "ports[0]", Reason: "only attributes can be renamed"}, err)
+
+	move, err := op.Move(parser, "image", "spec.image")
+	assert.NoError(t, err)
+	_, err = Mutate(content, move[0])
+	assert.Equal(t, &PathError{Path: "image", Reason: "not found in the document"}, err)
+
+	patch, err := op.Patch(Patch{
+		{Op: PatchTest, Path: "/ports/1", Value: 443},
+	})
+	assert.NoError(t, err)
+	_, err = Mutate(content, patch[0])
+	var pathErr *PathError
+	assert.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, "ports[1]", pathErr.Path)
+
+	patch, err = op.Patch(Patch{
+		{Op: PatchTest, Path: "/ports/0", Value: 443},
+	})
+	assert.NoError(t, err)
+	_, err = Mutate(content, patch[0])
+	var testErr *TestFailedError
+	assert.ErrorAs(t, err, &testErr)
+	assert.Equal(t, &TestFailedError{Path: "ports[0]", Expected: 443, Actual: 80}, testErr)
+
+	_, err = ParsePatch([]byte(`[{"op":"copy","path":"/a"}]`))
+	assert.Equal(t, &PatchError{Op: PatchCopy, Reason: "missing from"}, err)
+
+	_, err = NewMerger(WithConflictPolicy(FailOnConflict)).Merge(content, map[string]any{"ports": "80"})
+	assert.Equal(t, &TypeMismatchError{Path: "ports", Expected: "array", Actual: "string"}, err)
+}
+
+func Test_mutator_applyError(t *testing.T) {
+	parser := &Parser{}
+	mutators, err := NewOperation().Apply(parser, sanitizer.PathFuncList{
+		{Path: "name", Func: reflect.ValueOf(func(n int) int { return n })},
+	})
+	assert.NoError(t, err)
+	_, err = Mutate(map[string]any{"name": "api"}, mutators[0])
+	var applyErr *ApplyError
+	assert.ErrorAs(t, err, &applyErr)
+	assert.Equal(t, "name", applyErr.Path)
+	assert.EqualError(t, applyErr.Err, "cannot use 'api' as 'int'")
+}
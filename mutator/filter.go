@@ -0,0 +1,353 @@
+package mutator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ivancorrales/knoa/internal"
+)
+
+const (
+	filterPrefix = "?("
+	filterSuffix = ")"
+)
+
+// predicate is a compiled filter expression such as ?(@.age > 30 && @.active).
+type predicate interface {
+	match(item any) bool
+}
+
+type operand interface {
+	resolve(item any) (any, bool)
+}
+
+type literal struct {
+	value any
+}
+
+func (l literal) resolve(any) (any, bool) {
+	return l.value, true
+}
+
+// itemPath refers to the item under evaluation (@) or one of its attributes or items.
+type itemPath []string
+
+func (p itemPath) resolve(item any) (any, bool) {
+	return valueAt(item, p)
+}
+
+type truthy struct {
+	operand operand
+}
+
+func (t truthy) match(item any) bool {
+	value, found := t.operand.resolve(item)
+	if !found {
+		return false
+	}
+	if b, ok := value.(bool); ok {
+		return b
+	}
+	return value != nil
+}
+
+type comparison struct {
+	op          string
+	left, right operand
+}
+
+func (c comparison) match(item any) bool {
+	left, leftFound := c.left.resolve(item)
+	right, rightFound := c.right.resolve(item)
+	if !leftFound || !rightFound {
+		return false
+	}
+	switch c.op {
+	case "==":
+		return internal.Equal(left, right)
+	case "!=":
+		return !internal.Equal(left, right)
+	}
+	cmp, ok := compare(left, right)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func compare(left, right any) (int, bool) {
+	if x, ok := internal.ToFloat(left); ok {
+		y, isNumber := internal.ToFloat(right)
+		switch {
+		case !isNumber:
+			return 0, false
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+	x, ok := left.(string)
+	y, isString := right.(string)
+	if !ok || !isString {
+		return 0, false
+	}
+	return strings.Compare(x, y), true
+}
+
+type logical struct {
+	and         bool
+	left, right predicate
+}
+
+func (l logical) match(item any) bool {
+	if l.and {
+		return l.left.match(item) && l.right.match(item)
+	}
+	return l.left.match(item) || l.right.match(item)
+}
+
+type not struct {
+	predicate predicate
+}
+
+func (n not) match(item any) bool {
+	return !n.predicate.match(item)
+}
+
+func isFilter(index string) bool {
+	return strings.HasPrefix(index, filterPrefix) && strings.HasSuffix(index, filterSuffix)
+}
+
+// parseFilter compiles a filter index, i.e. ?(@.type == "disk"), into a predicate.
+func parseFilter(index string) (predicate, error) {
+	expr := strings.TrimSuffix(strings.TrimPrefix(index, filterPrefix), filterSuffix)
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter '%s': %w", index, err)
+	}
+	p := &filterParser{tokens: tokens}
+	out, err := p.or()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected '%s'", p.tokens[p.pos])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter '%s': %w", index, err)
+	}
+	return out, nil
+}
+
+var filterOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ".", "@"}
+
+func tokenizeFilter(expr string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+			continue
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(expr) && expr[end] != c {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, expr[i:end+1])
+			i = end + 1
+			continue
+		case c == '-' || isDigit(c):
+			end := i + 1
+			for end < len(expr) && (isDigit(expr[end]) || expr[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, expr[i:end])
+			i = end
+			continue
+		case c == '_' || unicode.IsLetter(rune(c)):
+			end := i + 1
+			for end < len(expr) && isNameChar(expr[end]) {
+				end++
+			}
+			tokens = append(tokens, expr[i:end])
+			i = end
+			continue
+		}
+		matched := false
+		for _, op := range filterOperators {
+			if strings.HasPrefix(expr[i:], op) {
+				tokens = append(tokens, op)
+				i += len(op)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("unexpected character '%c'", c)
+		}
+	}
+	return tokens, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c == '-' || c == '/' || isDigit(c) || unicode.IsLetter(rune(c))
+}
+
+type filterParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *filterParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *filterParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *filterParser) or() (predicate, error) {
+	left, err := p.and()
+	for err == nil && p.peek() == "||" {
+		p.next()
+		var right predicate
+		if right, err = p.and(); err == nil {
+			left = logical{left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *filterParser) and() (predicate, error) {
+	left, err := p.unary()
+	for err == nil && p.peek() == "&&" {
+		p.next()
+		var right predicate
+		if right, err = p.unary(); err == nil {
+			left = logical{and: true, left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *filterParser) unary() (predicate, error) {
+	switch p.peek() {
+	case "!":
+		p.next()
+		inner, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return not{predicate: inner}, nil
+	case "(":
+		p.next()
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		return inner, nil
+	}
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	switch op := p.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.next()
+		right, rightErr := p.operand()
+		if rightErr != nil {
+			return nil, rightErr
+		}
+		return comparison{op: op, left: left, right: right}, nil
+	}
+	return truthy{operand: left}, nil
+}
+
+func (p *filterParser) operand() (operand, error) {
+	token := p.next()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case token == "@":
+		return p.itemPath()
+	case token[0] == '"' || token[0] == '\'':
+		return literal{value: unquote(token)}, nil
+	case token == "true", token == "false":
+		return literal{value: token == "true"}, nil
+	case token == "null":
+		return literal{value: nil}, nil
+	}
+	number, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected '%s'", token)
+	}
+	return literal{value: number}, nil
+}
+
+func (p *filterParser) itemPath() (operand, error) {
+	path := itemPath{}
+	for {
+		switch p.peek() {
+		case ".":
+			p.next()
+			token := p.next()
+			if token == "" || strings.ContainsRune("@.[]()!=<>&|", rune(token[0])) {
+				return nil, fmt.Errorf("missing attribute name after '.'")
+			}
+			path = append(path, unquote(token))
+		case "[":
+			p.next()
+			index := p.next()
+			if !isPointerIndex(index) || p.next() != "]" {
+				return nil, fmt.Errorf("invalid index '%s'", index)
+			}
+			path = append(path, index)
+		default:
+			return path, nil
+		}
+	}
+}
+
+func unquote(token string) string {
+	if len(token) < 2 || (token[0] != '"' && token[0] != '\'') {
+		return token
+	}
+	body := token[1 : len(token)-1]
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] == '\\' && i+1 < len(body) {
+			i++
+		}
+		b.WriteByte(body[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package mutator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseFilter(t *testing.T) {
+	item := map[string]any{
+		"name":   "disk-1",
+		"type":   "disk",
+		"size":   float64(500),
+		"active": true,
+		"tags":   []any{"ssd", "fast"},
+		"owner":  map[string]any{"first-name": "John"},
+	}
+	tests := []struct {
+		name    string
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "Equal string", expr: `?(@.type == "disk")`, want: true},
+		{name: "Equal string with single quotes", expr: `?(@.type == 'cpu')`, want: false},
+		{name: "Not equal", expr: `?(@.type != "cpu")`, want: true},
+		{name: "Greater than", expr: `?(@.size > 300)`, want: true},
+		{name: "Less or equal", expr: `?(@.size <= 499.5)`, want: false},
+		{name: "Compare strings", expr: `?(@.name < "disk-2")`, want: true},
+		{name: "Compare different types", expr: `?(@.name > 3)`, want: false},
+		{name: "Existence", expr: `?(@.active)`, want: true},
+		{name: "Missing attribute", expr: `?(@.email)`, want: false},
+		{name: "Negation", expr: `?(!@.email)`, want: true},
+		{name: "And", expr: `?(@.type == "disk" && @.size > 1000)`, want: false},
+		{name: "Or", expr: `?(@.type == "disk" || @.size > 1000)`, want: true},
+		{name: "Precedence", expr: `?(@.size > 1000 && @.active || @.type == "disk")`, want: true},
+		{name: "Parentheses", expr: `?(@.size > 1000 && (@.active || @.type == "disk"))`, want: false},
+		{name: "Nested attributes and items", expr: `?(@.owner.first-name == "John" && @.tags[1] == "fast")`, want: true},
+		{name: "Null", expr: `?(@.owner.last == null)`, want: false},
+		{name: "Missing operand", expr: `?(@.size >)`, wantErr: true},
+		{name: "Unbalanced parentheses", expr: `?((@.size > 3)`, wantErr: true},
+		{name: "Unterminated string", expr: `?(@.type == "disk)`, wantErr: true},
+		{name: "Unexpected character", expr: `?(@.size # 3)`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFilter(tt.expr)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.match(item))
+		})
+	}
+}
+
+func Test_mutator_filter(t *testing.T) {
+	users := func() []any {
+		return []any{
+			map[string]any{"name": "John", "age": float64(40)},
+			map[string]any{"name": "Jane", "age": float64(25)},
+			map[string]any{"name": "Tom", "age": float64(35)},
+		}
+	}
+	tests := []struct {
+		name    string
+		mutator *Mutator
+		want    []any
+	}{
+		{
+			name: "Set an attribute of the matching items",
+			mutator: &Mutator{
+				index: "?(@.age > 30)",
+				child: &Mutator{name: "senior", value: true},
+			},
+			want: []any{
+				map[string]any{"name": "John", "age": float64(40), "senior": true},
+				map[string]any{"name": "Jane", "age": float64(25)},
+				map[string]any{"name": "Tom", "age": float64(35), "senior": true},
+			},
+		},
+		{
+			name:    "Unset the matching items",
+			mutator: &Mutator{index: `?(@.name != "Jane")`, operation: unsetOp},
+			want: []any{
+				map[string]any{"name": "Jane", "age": float64(25)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.mutator.ToArray(users())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package mutator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	wildcardIndex  = "*"
+	sliceSeparator = ":"
+)
+
+func (m *Mutator) isSlice() bool {
+	return !isFilter(m.index) && strings.Contains(m.index, sliceSeparator)
+}
+
+// isRange reports whether the index refers to several items of the array.
+func (m *Mutator) isRange() bool {
+	return m.index == wildcardIndex || isFilter(m.index) || m.isSlice()
+}
+
+// resolveIndex turns negative indexes, that count from the end of the array, into positions.
+func (m *Mutator) resolveIndex(length int) (int, error) {
+	index, err := strconv.Atoi(m.index)
+	if err != nil {
+		return 0, err
+	}
+	if index < 0 {
+		if index+length < 0 {
+			return index, m.indexOutOfRange(index, length)
+		}
+		index += length
+	}
+	return index, nil
+}
+
+// rangeIndexes returns the positions of the items matched by a wildcard, a filter or a slice.
+func (m *Mutator) rangeIndexes(items []any) ([]int, error) {
+	switch {
+	case m.index == wildcardIndex:
+		indexes := make([]int, len(items))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes, nil
+	case isFilter(m.index):
+		filter := m.filter
+		if filter == nil {
+			var err error
+			if filter, err = parseFilter(m.index); err != nil {
+				return nil, err
+			}
+		}
+		var indexes []int
+		for i := range items {
+			if filter.match(items[i]) {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes, nil
+	}
+	return sliceIndexes(m.index, len(items))
+}
+
+// sliceIndexes follows the semantics of the Python slices: start:stop:step.
+func sliceIndexes(expr string, length int) ([]int, error) {
+	parts := strings.Split(expr, sliceSeparator)
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("invalid slice '%s'", expr)
+	}
+	bounds := make([]*int, 3)
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slice '%s'", expr)
+		}
+		bounds[i] = &value
+	}
+	step := 1
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+	if step == 0 {
+		return nil, fmt.Errorf("invalid slice '%s': step cannot be zero", expr)
+	}
+	start, stop := 0, length
+	if step < 0 {
+		start, stop = length-1, -1
+	}
+	if bounds[0] != nil {
+		start = sliceBound(*bounds[0], length, step)
+	}
+	if bounds[1] != nil {
+		stop = sliceBound(*bounds[1], length, step)
+	}
+	var indexes []int
+	for i := start; (step > 0 && i < stop) || (step < 0 && i > stop); i += step {
+		indexes = append(indexes, i)
+	}
+	return indexes, nil
+}
+
+func sliceBound(value, length, step int) int {
+	lower, upper := 0, length
+	if step < 0 {
+		lower, upper = -1, length-1
+	}
+	if value < 0 {
+		value += length
+	}
+	if value < lower {
+		return lower
+	}
+	if value > upper {
+		return upper
+	}
+	return value
+}
+
+// removeIndexes removes the items in the given positions, whatever the order they come in.
+func removeIndexes(content []any, indexes []int) []any {
+	sorted := append([]int{}, indexes...)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	for _, index := range sorted {
+		content = append(content[:index], content[index+1:]...)
+	}
+	return content
+}
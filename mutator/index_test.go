@@ -0,0 +1,99 @@
+package mutator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_sliceIndexes(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		length  int
+		want    []int
+		wantErr bool
+	}{
+		{name: "Start and stop", expr: "1:3", length: 5, want: []int{1, 2}},
+		{name: "Only stop", expr: ":2", length: 5, want: []int{0, 1}},
+		{name: "Only start", expr: "3:", length: 5, want: []int{3, 4}},
+		{name: "Only step", expr: "::2", length: 5, want: []int{0, 2, 4}},
+		{name: "Negative bounds", expr: "-3:-1", length: 5, want: []int{2, 3}},
+		{name: "Negative step", expr: "::-2", length: 5, want: []int{4, 2, 0}},
+		{name: "Bounds beyond the length", expr: "2:10", length: 4, want: []int{2, 3}},
+		{name: "Empty range", expr: "3:1", length: 5, want: nil},
+		{name: "Zero step", expr: "::0", length: 5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sliceIndexes(tt.expr, tt.length)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_mutator_ranges(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutator *Mutator
+		content []any
+		want    []any
+		wantErr bool
+	}{
+		{
+			name:    "Set the last item",
+			mutator: &Mutator{index: "-1", value: "z"},
+			content: []any{"a", "b", "c"},
+			want:    []any{"a", "b", "z"},
+		},
+		{
+			name:    "Set a negative index out of range",
+			mutator: &Mutator{index: "-4", value: "z"},
+			content: []any{"a", "b", "c"},
+			wantErr: true,
+		},
+		{
+			name:    "Unset the last item",
+			mutator: &Mutator{index: "-1", operation: unsetOp},
+			content: []any{"a", "b", "c"},
+			want:    []any{"a", "b"},
+		},
+		{
+			name:    "Set the items of a slice",
+			mutator: &Mutator{index: "1:3", value: "z"},
+			content: []any{"a", "b", "c", "d"},
+			want:    []any{"a", "z", "z", "d"},
+		},
+		{
+			name:    "Unset the items of a slice with step",
+			mutator: &Mutator{index: "::2", operation: unsetOp},
+			content: []any{"a", "b", "c", "d", "e"},
+			want:    []any{"b", "d"},
+		},
+		{
+			name: "Set an attribute of the items of a slice",
+			mutator: &Mutator{
+				index: ":2",
+				child: &Mutator{name: "done", value: true},
+			},
+			content: []any{map[string]any{}, map[string]any{}, map[string]any{}},
+			want:    []any{map[string]any{"done": true}, map[string]any{"done": true}, map[string]any{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.mutator.ToArray(tt.content)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package mutator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int32
+
+// syntaxChars are the characters that can't be part of an unquoted name unless they are escaped.
+const syntaxChars = `.[]'"*`
+
+const (
+	nameToken tokenKind = iota
+	quotedToken
+	dotToken
+	descentToken
+	bracketToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// column is the 1-based position of the token in the path expression.
+	column int
+}
+
+type lexer struct {
+	path  []rune
+	pos   int
+	input string
+}
+
+func (l *lexer) fail(column int, format string, args ...any) error {
+	return &PathError{Path: l.input, Column: column, Reason: fmt.Sprintf(format, args...)}
+}
+
+func tokenize(input string) ([]token, error) {
+	l := &lexer{path: []rune(input), input: input}
+	var tokens []token
+	for l.pos < len(l.path) {
+		column := l.pos + 1
+		switch c := l.path[l.pos]; {
+		case c == '.':
+			if l.pos+1 < len(l.path) && l.path[l.pos+1] == '.' {
+				tokens = append(tokens, token{kind: descentToken, text: recursiveSeparator, column: column})
+				l.pos += 2
+				continue
+			}
+			tokens = append(tokens, token{kind: dotToken, text: ".", column: column})
+			l.pos++
+		case c == '[':
+			text, err := l.bracket()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: bracketToken, text: text, column: column})
+		case c == ']':
+			return nil, l.fail(column, "unexpected ']'")
+		case c == '"' || c == '\'':
+			text, err := l.quoted()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: quotedToken, text: text, column: column})
+		default:
+			text, literal, err := l.name()
+			if err != nil {
+				return nil, err
+			}
+			kind := nameToken
+			if literal {
+				kind = quotedToken
+			}
+			tokens = append(tokens, token{kind: kind, text: text, column: column})
+		}
+	}
+	return tokens, nil
+}
+
+// name reads an unquoted attribute name, where a backslash escapes the next character. The name
+// is literal, as if it was quoted, when any of the characters of the path syntax is escaped.
+func (l *lexer) name() (string, bool, error) {
+	var b strings.Builder
+	literal := false
+	for l.pos < len(l.path) {
+		c := l.path[l.pos]
+		switch {
+		case c == '.' || c == '[':
+			return b.String(), literal, nil
+		case c == ']' || c == '"' || c == '\'' || unicode.IsSpace(c):
+			return "", false, l.fail(l.pos+1, "unexpected '%c'", c)
+		case c == '\\':
+			if l.pos+1 >= len(l.path) {
+				return "", false, l.fail(l.pos+1, "unfinished escape sequence")
+			}
+			l.pos++
+			c = l.path[l.pos]
+			literal = literal || strings.ContainsRune(syntaxChars, c) || unicode.IsSpace(c)
+		}
+		b.WriteRune(c)
+		l.pos++
+	}
+	return b.String(), literal, nil
+}
+
+// quoted reads a key between single or double quotes, where a backslash escapes the next character.
+func (l *lexer) quoted() (string, error) {
+	start := l.pos
+	quote := l.path[l.pos]
+	var b strings.Builder
+	for l.pos++; l.pos < len(l.path); l.pos++ {
+		c := l.path[l.pos]
+		switch c {
+		case quote:
+			l.pos++
+			return b.String(), nil
+		case '\\':
+			if l.pos+1 >= len(l.path) {
+				return "", l.fail(l.pos+1, "unfinished escape sequence")
+			}
+			l.pos++
+			c = l.path[l.pos]
+		}
+		b.WriteRune(c)
+	}
+	return "", l.fail(start+1, "unterminated quoted key")
+}
+
+// bracket reads the raw content between brackets, which may contain quoted keys and nested
+// brackets or parentheses in the case of filters.
+func (l *lexer) bracket() (string, error) {
+	start := l.pos
+	depth := 0
+	for l.pos++; l.pos < len(l.path); l.pos++ {
+		switch c := l.path[l.pos]; c {
+		case '"', '\'':
+			for l.pos++; l.pos < len(l.path) && l.path[l.pos] != c; l.pos++ {
+				if l.path[l.pos] == '\\' {
+					l.pos++
+				}
+			}
+			if l.pos >= len(l.path) {
+				return "", l.fail(start+1, "unterminated quoted key")
+			}
+		case '(', '[':
+			depth++
+		case ')':
+			depth--
+		case ']':
+			if depth == 0 {
+				text := string(l.path[start+1 : l.pos])
+				l.pos++
+				return text, nil
+			}
+			depth--
+		}
+	}
+	return "", l.fail(start+1, "missing ']'")
+}
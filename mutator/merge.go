@@ -0,0 +1,195 @@
+package mutator
+
+import (
+	"github.com/ivancorrales/knoa/internal"
+)
+
+type ArrayStrategy int32
+
+const (
+	ReplaceArrays ArrayStrategy = iota
+	AppendArrays
+	MergeArraysByIndex
+	MergeArraysByKey
+)
+
+type ConflictPolicy int32
+
+const (
+	OverrideOnConflict ConflictPolicy = iota
+	KeepOnConflict
+	FailOnConflict
+)
+
+type merger struct {
+	arrayStrategy  ArrayStrategy
+	keyField       string
+	conflictPolicy ConflictPolicy
+	// mergePatch enables the RFC 7396 semantics: null values remove the attributes
+	// and objects are merged into any other type.
+	mergePatch bool
+}
+
+type MergeOpt func(m *merger)
+
+func WithArrayStrategy(strategy ArrayStrategy) func(m *merger) {
+	return func(m *merger) {
+		m.arrayStrategy = strategy
+	}
+}
+
+func WithArrayKeyField(keyField string) func(m *merger) {
+	return func(m *merger) {
+		m.arrayStrategy = MergeArraysByKey
+		m.keyField = keyField
+	}
+}
+
+func WithConflictPolicy(policy ConflictPolicy) func(m *merger) {
+	return func(m *merger) {
+		m.conflictPolicy = policy
+	}
+}
+
+func withMergePatch() func(m *merger) {
+	return func(m *merger) {
+		m.mergePatch = true
+		m.arrayStrategy = ReplaceArrays
+		m.conflictPolicy = OverrideOnConflict
+	}
+}
+
+func NewMerger(opts ...MergeOpt) *merger {
+	m := &merger{}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+type mergeValue struct {
+	content any
+	merger  *merger
+}
+
+func (mg *merger) Merge(dst, src any) (any, error) {
+	return mg.merge("", internal.Normalize(dst), src)
+}
+
+func (mg *merger) merge(path string, dst, src any) (any, error) {
+	switch srcValue := src.(type) {
+	case map[string]any:
+		dstValue, ok := dst.(map[string]any)
+		if !ok && mg.mergePatch {
+			dstValue, ok = make(map[string]any), true
+		}
+		if !ok {
+			return mg.conflict(path, dst, src)
+		}
+		return mg.mergeMaps(path, dstValue, srcValue)
+	case []any:
+		dstValue, ok := dst.([]any)
+		if !ok {
+			return mg.conflict(path, dst, src)
+		}
+		return mg.mergeArrays(path, dstValue, srcValue)
+	default:
+		if dst != nil && internal.KindOf(dst) != internal.KindOf(src) {
+			return mg.conflict(path, dst, src)
+		}
+		return src, nil
+	}
+}
+
+func (mg *merger) conflict(path string, dst, src any) (any, error) {
+	if dst == nil {
+		return internal.Normalize(src), nil
+	}
+	switch mg.conflictPolicy {
+	case KeepOnConflict:
+		return dst, nil
+	case FailOnConflict:
+		return dst, &TypeMismatchError{Path: path, Expected: internal.KindOf(dst), Actual: internal.KindOf(src)}
+	default:
+		return internal.Normalize(src), nil
+	}
+}
+
+func (mg *merger) mergeMaps(path string, dst, src map[string]any) (map[string]any, error) {
+	for k, v := range src {
+		childPath := internal.AttributePath(path, k)
+		if v == nil && mg.mergePatch {
+			delete(dst, k)
+			continue
+		}
+		current, found := dst[k]
+		if !found && !mg.mergePatch {
+			dst[k] = internal.Normalize(v)
+			continue
+		}
+		merged, err := mg.merge(childPath, current, v)
+		if err != nil {
+			return dst, err
+		}
+		dst[k] = merged
+	}
+	return dst, nil
+}
+
+func (mg *merger) mergeArrays(path string, dst, src []any) ([]any, error) {
+	switch mg.arrayStrategy {
+	case AppendArrays:
+		for _, v := range src {
+			dst = append(dst, internal.Normalize(v))
+		}
+		return dst, nil
+	case MergeArraysByIndex:
+		for i, v := range src {
+			if i >= len(dst) {
+				dst = append(dst, internal.Normalize(v))
+				continue
+			}
+			merged, err := mg.merge(internal.IndexPath(path, i), dst[i], v)
+			if err != nil {
+				return dst, err
+			}
+			dst[i] = merged
+		}
+		return dst, nil
+	case MergeArraysByKey:
+		for _, v := range src {
+			i := mg.indexByKey(dst, v)
+			if i < 0 {
+				dst = append(dst, internal.Normalize(v))
+				continue
+			}
+			merged, err := mg.merge(internal.IndexPath(path, i), dst[i], v)
+			if err != nil {
+				return dst, err
+			}
+			dst[i] = merged
+		}
+		return dst, nil
+	default:
+		return internal.Normalize(src).([]any), nil
+	}
+}
+
+func (mg *merger) indexByKey(items []any, item any) int {
+	itemValue, ok := item.(map[string]any)
+	if !ok {
+		return -1
+	}
+	key, found := itemValue[mg.keyField]
+	if !found {
+		return -1
+	}
+	for i := range items {
+		if current, isMap := items[i].(map[string]any); isMap {
+			if currentKey, hasKey := current[mg.keyField]; hasKey && internal.Equal(currentKey, key) {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,184 @@
+package mutator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_merger_merge(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []MergeOpt
+		dst     any
+		src     any
+		want    any
+		wantErr bool
+	}{
+		{
+			name: "Maps are merged recursively",
+			dst: map[string]any{
+				"name": "api",
+				"spec": map[string]any{"replicas": 1, "image": "api:1.0"},
+			},
+			src: map[string]any{
+				"spec": map[string]any{"replicas": 3},
+				"env":  "prod",
+			},
+			want: map[string]any{
+				"name": "api",
+				"env":  "prod",
+				"spec": map[string]any{"replicas": 3, "image": "api:1.0"},
+			},
+		},
+		{
+			name: "Arrays are replaced by default",
+			dst:  map[string]any{"ports": []any{80, 443}},
+			src:  map[string]any{"ports": []any{8080}},
+			want: map[string]any{"ports": []any{8080}},
+		},
+		{
+			name: "Arrays are appended",
+			opts: []MergeOpt{WithArrayStrategy(AppendArrays)},
+			dst:  map[string]any{"ports": []any{80, 443}},
+			src:  map[string]any{"ports": []any{8080}},
+			want: map[string]any{"ports": []any{80, 443, 8080}},
+		},
+		{
+			name: "Arrays are merged by index",
+			opts: []MergeOpt{WithArrayStrategy(MergeArraysByIndex)},
+			dst: []any{
+				map[string]any{"name": "Tim", "age": 29},
+				map[string]any{"name": "Bob", "age": 40},
+			},
+			src: []any{
+				map[string]any{"age": 30},
+				nil,
+				map[string]any{"name": "Jane"},
+			},
+			want: []any{
+				map[string]any{"name": "Tim", "age": 30},
+				nil,
+				map[string]any{"name": "Jane"},
+			},
+		},
+		{
+			name: "Arrays are merged by key field",
+			opts: []MergeOpt{WithArrayKeyField("id")},
+			dst: []any{
+				map[string]any{"id": float64(1), "name": "Tim"},
+				map[string]any{"id": float64(2), "name": "Bob"},
+			},
+			src: []any{
+				map[string]any{"id": 2, "age": 40},
+				map[string]any{"id": 3, "name": "Jane"},
+			},
+			want: []any{
+				map[string]any{"id": float64(1), "name": "Tim"},
+				map[string]any{"id": 2, "name": "Bob", "age": 40},
+				map[string]any{"id": 3, "name": "Jane"},
+			},
+		},
+		{
+			name: "Type conflicts are overridden by default",
+			dst:  map[string]any{"spec": map[string]any{"replicas": 1}},
+			src:  map[string]any{"spec": "none"},
+			want: map[string]any{"spec": "none"},
+		},
+		{
+			name: "Type conflicts keep the current value",
+			opts: []MergeOpt{WithConflictPolicy(KeepOnConflict)},
+			dst:  map[string]any{"spec": map[string]any{"replicas": 1}},
+			src:  map[string]any{"spec": "none"},
+			want: map[string]any{"spec": map[string]any{"replicas": 1}},
+		},
+		{
+			name:    "Type conflicts fail",
+			opts:    []MergeOpt{WithConflictPolicy(FailOnConflict)},
+			dst:     map[string]any{"spec": map[string]any{"ports": []any{80}}},
+			src:     map[string]any{"spec": map[string]any{"ports": "80"}},
+			wantErr: true,
+		},
+		{
+			name: "Scalars of a different type keep the current value",
+			opts: []MergeOpt{WithConflictPolicy(KeepOnConflict)},
+			dst:  map[string]any{"replicas": 1, "name": "api"},
+			src:  map[string]any{"replicas": "two", "name": "web"},
+			want: map[string]any{"replicas": 1, "name": "web"},
+		},
+		{
+			name:    "Scalars of a different type fail",
+			opts:    []MergeOpt{WithConflictPolicy(FailOnConflict)},
+			dst:     map[string]any{"replicas": 1},
+			src:     map[string]any{"replicas": "1"},
+			wantErr: true,
+		},
+		{
+			name: "Numbers of a different Go type don't conflict",
+			opts: []MergeOpt{WithConflictPolicy(FailOnConflict)},
+			dst:  map[string]any{"replicas": 1},
+			src:  map[string]any{"replicas": 2.0},
+			want: map[string]any{"replicas": 2.0},
+		},
+		{
+			name: "Merge patch removes null attributes",
+			opts: []MergeOpt{withMergePatch()},
+			dst: map[string]any{
+				"title":  "Goodbye!",
+				"author": map[string]any{"givenName": "John", "familyName": "Doe"},
+				"tags":   []any{"example", "sample"},
+			},
+			src: map[string]any{
+				"title":       "Hello!",
+				"phoneNumber": "+01-123-456-7890",
+				"author":      map[string]any{"familyName": nil},
+				"tags":        []any{"example"},
+			},
+			want: map[string]any{
+				"title":       "Hello!",
+				"author":      map[string]any{"givenName": "John"},
+				"tags":        []any{"example"},
+				"phoneNumber": "+01-123-456-7890",
+			},
+		},
+		{
+			name: "Merge patch replaces non objects with objects",
+			opts: []MergeOpt{withMergePatch()},
+			dst:  map[string]any{"a": []any{"b"}, "c": "d"},
+			src:  map[string]any{"a": map[string]any{"b": "c", "d": nil}, "e": map[string]any{"f": nil}},
+			want: map[string]any{"a": map[string]any{"b": "c"}, "c": "d", "e": map[string]any{}},
+		},
+		{
+			name: "Merge patch keeps nulls inside arrays",
+			opts: []MergeOpt{withMergePatch()},
+			dst:  map[string]any{"a": "b"},
+			src:  map[string]any{"a": []any{nil, 1}},
+			want: map[string]any{"a": []any{nil, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMerger(tt.opts...).Merge(tt.dst, tt.src)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_merger_conflictPaths(t *testing.T) {
+	m := NewMerger(WithConflictPolicy(FailOnConflict), WithArrayStrategy(MergeArraysByIndex))
+	_, err := m.Merge(
+		map[string]any{"labels": map[string]any{"app.kubernetes.io/name": "api"}},
+		map[string]any{"labels": map[string]any{"app.kubernetes.io/name": 1}},
+	)
+	assert.Equal(t, &TypeMismatchError{Path: `labels."app.kubernetes.io/name"`, Expected: "string", Actual: "number"}, err)
+	_, err = m.Merge(
+		map[string]any{"ports": []any{80, map[string]any{"port": 443}}},
+		map[string]any{"ports": []any{80, "443"}},
+	)
+	assert.Equal(t, &TypeMismatchError{Path: "ports[1]", Expected: "map", Actual: "string"}, err)
+}
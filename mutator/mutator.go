@@ -5,7 +5,13 @@ import (
 	"reflect"
 	"strconv"
 
-	"github.com/fatih/structs"
+	"github.com/ivancorrales/knoa/internal"
+)
+
+const (
+	appendIndex        = "+"
+	pointerAppendIndex = "-"
+	prependIndex       = "^"
 )
 
 type Mutator struct {
@@ -14,6 +20,12 @@ type Mutator struct {
 	child     *Mutator
 	value     any
 	operation operationCode
+	filter    predicate
+	recursive bool
+	// literal is set for quoted keys and JSON Pointer tokens, whose names are never wildcards.
+	literal bool
+	// loc is the location, within the document, of the node that contains the one the mutator is applied to.
+	loc location
 }
 
 func (m *Mutator) addValueToNode(v any) {
@@ -31,37 +43,103 @@ func (m *Mutator) Child() *Mutator {
 
 func (m *Mutator) IsArray() bool {
 	_, err := strconv.Atoi(m.index)
-	return err == nil || m.index == "*"
+	return err == nil || m.isRange() || m.isNewItem()
+}
+
+// createsArray reports whether the node is applied over an array, that is created when the content is missing.
+func (m *Mutator) createsArray(content any) bool {
+	if !m.IsArray() {
+		return false
+	}
+	if content == nil {
+		return m.name == "" || m.literal
+	}
+	_, isArray := content.([]any)
+	return m.name == "" || isArray
+}
+
+// isNewItem reports whether the index refers to an item to be appended or prepended to the array.
+func (m *Mutator) isNewItem() bool {
+	return m.index == appendIndex || m.index == pointerAppendIndex || m.index == prependIndex
 }
 
 func (m *Mutator) applyValue(in any) any {
 	val := reflect.ValueOf(m.value)
 	switch val.Kind() {
-	case reflect.Struct:
-		return structs.Map(val.Interface())
 	case reflect.Func:
-		x := reflect.TypeOf(m.value)
-		if x.NumIn() != 1 || x.NumOut() != 1 {
+		f, err := newApplyFunc(val)
+		if err != nil {
 			return nil
 		}
-		inVal := reflect.ValueOf(in)
-		if x.In(0).Kind() != inVal.Kind() {
+		out, err := f.call("", in)
+		if err != nil {
 			return nil
 		}
-		out := val.Call([]reflect.Value{inVal})
-		return out[0].String()
-	case reflect.Slice, reflect.Array:
-		out := make([]any, val.Len())
-		for i := 0; i < val.Len(); i++ {
-			if val.Index(i).Kind() == reflect.Struct {
-				out[i] = structs.Map(val.Index(i).Interface())
-			} else {
-				out[i] = val.Index(i).Interface()
-			}
-		}
 		return out
 	default:
-		return m.value
+		// The value is copied, so the changes in the document don't reach the mutator. Structs are
+		// turned into maps the same way FromStruct does.
+		return internal.Normalize(m.value)
+	}
+}
+
+// isSelf reports whether the node is the value itself, instead of one of its attributes or items.
+// Literal nodes without a name refer to the empty key, as in the JSON Pointer '/'.
+func (m *Mutator) isSelf() bool {
+	return m.child == nil && m.name == "" && m.index == "" && !m.literal
+}
+
+func (m *Mutator) merge(in any) (any, error) {
+	v, ok := m.value.(*mergeValue)
+	if !ok {
+		return in, fmt.Errorf("invalid merge value")
+	}
+	return v.merger.Merge(in, v.content)
+}
+
+// toSelf applies those operations that target the node itself instead of one of its attributes or items.
+func (m *Mutator) toSelf(content any) (any, error) {
+	switch m.operation {
+	case mergeOp:
+		return m.merge(content)
+	case patchOp:
+		return m.patch(content)
+	case moveOp, copyOp:
+		return m.transfer(content)
+	case testOp:
+		if !internal.Equal(content, m.value) {
+			return content, testFailed(m.loc.path, m.value, content)
+		}
+		return content, nil
+	case setOp, insertOp, replaceOp, restoreOp:
+		return internal.Normalize(m.applyValue(content)), nil
+	default:
+		return content, fmt.Errorf("unsupported operation over the whole document")
+	}
+}
+
+func Mutate(content any, m Mutator) (any, error) {
+	if m.child == nil {
+		return content, fmt.Errorf("invalid mutator")
+	}
+	// the nodes are updated while they are applied, so the mutator can be shared between documents
+	child := m.clone().Child()
+	child.loc = location{}
+	if m.operation == applyCtxOp {
+		child.loc.root = internal.Normalize(content)
+	}
+	switch c := content.(type) {
+	case []any:
+		return child.ToArray(c)
+	case map[string]any:
+		return child.ToMap(c)
+	case nil:
+		if child.createsArray(nil) {
+			return child.ToArray(nil)
+		}
+		return child.ToMap(nil)
+	default:
+		return content, &TypeMismatchError{Expected: "map or array", Actual: internal.KindOf(content)}
 	}
 }
 
@@ -69,15 +147,70 @@ func (m *Mutator) ToMap(content map[string]any) (map[string]any, error) {
 	if content == nil {
 		content = make(map[string]any)
 	}
+	if m.isSelf() {
+		self, err := m.toSelf(content)
+		if err != nil {
+			return content, err
+		}
+		out, ok := self.(map[string]any)
+		if !ok {
+			return content, m.typeMismatch("map", self)
+		}
+		return out, nil
+	}
+	if m.name == "" && m.IsArray() {
+		if m.operation == unsetOp {
+			return content, nil
+		}
+		return content, m.typeMismatch("array", content)
+	}
+	if m.recursive {
+		out, err := m.descend(content)
+		if err != nil {
+			return content, err
+		}
+		return out.(map[string]any), nil
+	}
+	if m.isWildcard() {
+		return m.toAttributes(content)
+	}
 	if m.child == nil {
 		switch m.operation {
 		case unsetOp:
 			delete(content, m.name)
 			return content, nil
+		case mergeOp:
+			merged, err := m.merge(content[m.name])
+			if err != nil {
+				return content, err
+			}
+			content[m.name] = merged
+			return content, nil
+		case insertOp, replaceOp:
+			content[m.name] = m.applyValue(content[m.name])
+			return content, nil
+		case testOp:
+			current, found := content[m.name]
+			if !found {
+				return content, notFound(m.attributeLocation().path)
+			}
+			if !internal.Equal(current, m.value) {
+				return content, testFailed(m.attributeLocation().path, m.value, current)
+			}
+			return content, nil
 		case applyOp:
-			if m.value != nil {
-				content[m.name] = m.applyValue(content[m.name])
+			current, found := content[m.name]
+			if !found {
+				return content, nil
+			}
+			value, err := m.apply(m.attributeLocation().path, current)
+			if err != nil {
+				return content, err
 			}
+			content[m.name] = value
+			return content, nil
+		case applyCtxOp:
+			content[m.name] = m.applyCtx(m.attributeLocation(), content, content[m.name])
 			return content, nil
 		default:
 			if m.value != nil {
@@ -87,9 +220,37 @@ func (m *Mutator) ToMap(content map[string]any) (map[string]any, error) {
 		}
 	}
 	mt := *m.Child()
-	c := content[m.name]
+	mt.loc = m.attributeLocation()
+	c, found := content[m.name]
+	if mt.recursive {
+		// there is nothing to search in an attribute that doesn't exist
+		if !found {
+			return content, nil
+		}
+		value, err := mt.descend(c)
+		if err != nil {
+			return nil, err
+		}
+		content[m.name] = value
+		return content, nil
+	}
+	if c == nil {
+		if mt.fansOut() {
+			return content, nil
+		}
+		switch m.operation {
+		case unsetOp, applyOp:
+			return content, nil
+		case testOp:
+			return content, notFound(m.attributeLocation().path)
+		}
+	}
+	kind := reflect.ValueOf(c).Kind()
+	if c == nil && mt.createsArray(nil) {
+		kind = reflect.Slice
+	}
 	var ok bool
-	switch reflect.ValueOf(c).Kind() {
+	switch kind {
 	case reflect.Slice, reflect.Array:
 		var childContent []any
 		if c == nil {
@@ -97,7 +258,7 @@ func (m *Mutator) ToMap(content map[string]any) (map[string]any, error) {
 		} else {
 			childContent, ok = c.([]any)
 			if !ok {
-				return content, fmt.Errorf("invalid array")
+				return content, mt.typeMismatch("array", c)
 			}
 		}
 		value, toArrayErr := mt.ToArray(childContent)
@@ -106,17 +267,19 @@ func (m *Mutator) ToMap(content map[string]any) (map[string]any, error) {
 		}
 		content[m.name] = value
 	default:
-		if m.operation == unsetOp {
-			delete(content, m.name)
-			return content, nil
-		}
 		var childContent map[string]any
 		if c == nil {
 			childContent = make(map[string]any)
 		} else {
 			childContent, ok = c.(map[string]any)
 			if !ok {
-				return content, fmt.Errorf("invalid array")
+				if m.operation == unsetOp {
+					return content, nil
+				}
+				if mt.IsArray() {
+					return content, mt.typeMismatch("array", c)
+				}
+				return content, mt.typeMismatch("map", c)
 			}
 		}
 		value, toMapErr := mt.ToMap(childContent)
@@ -133,35 +296,139 @@ func (m *Mutator) ToArray(content []any) ([]any, error) {
 	if content == nil {
 		content = make([]any, 0)
 	}
-	content = ensureSizeOfArray(content, m.index)
-
-	index, err := strconv.Atoi(m.index)
-	if err != nil {
-		if m.index == "*" {
-			var itemToArrayErr error
-			for i := 0; i < len(content); i++ {
-				content, itemToArrayErr = m.itemToArray(i, content)
-				if itemToArrayErr != nil {
-					return nil, itemToArrayErr
-				}
+	if m.isSelf() {
+		self, err := m.toSelf(content)
+		if err != nil {
+			return content, err
+		}
+		out, ok := self.([]any)
+		if !ok {
+			return content, m.typeMismatch("array", self)
+		}
+		return out, nil
+	}
+	if m.recursive {
+		out, err := m.descend(content)
+		if err != nil {
+			return content, err
+		}
+		return out.([]any), nil
+	}
+	if !m.IsArray() {
+		if m.operation == unsetOp {
+			return content, nil
+		}
+		return content, m.typeMismatch("map", content)
+	}
+	if m.child == nil && m.operation == insertOp {
+		return m.insertIntoArray(content)
+	}
+	if m.isNewItem() {
+		switch m.operation {
+		case unsetOp, applyOp:
+			return content, nil
+		case testOp:
+			return content, notFound(m.loc.path + m.segment())
+		}
+		if m.index == prependIndex {
+			return m.itemToArray(0, append([]any{nil}, content...))
+		}
+		return m.itemToArray(len(content), append(content, nil))
+	}
+	if m.isRange() {
+		indexes, err := m.rangeIndexes(content)
+		if err != nil {
+			return content, err
+		}
+		if m.child == nil && m.operation == unsetOp {
+			return removeIndexes(content, indexes), nil
+		}
+		for _, index := range indexes {
+			if content, err = m.itemToArray(index, content); err != nil {
+				return nil, err
 			}
+		}
+		return content, nil
+	}
+	index, err := m.resolveIndex(len(content))
+	if err != nil {
+		if m.operation == unsetOp {
 			return content, nil
 		}
-		return nil, err
+		return content, err
+	}
+	// only the operations that add values fill the array up to the index
+	if m.operation != unsetOp && m.operation != testOp && m.operation != applyOp {
+		content = ensureSizeOfArray(content, strconv.Itoa(index))
+	}
+	if index >= len(content) {
+		if m.operation == testOp {
+			return content, notFound(m.itemLocation(index).path)
+		}
+		return content, nil
 	}
 	return m.itemToArray(index, content)
 }
 
+// insertValue holds the values inserted by Insert, that are added to the array as a single block.
+type insertValue struct {
+	values []any
+}
+
+func (m *Mutator) insertIntoArray(content []any) ([]any, error) {
+	index := len(content)
+	if m.index == prependIndex {
+		index = 0
+	} else if !m.isNewItem() {
+		var err error
+		if index, err = m.resolveIndex(len(content)); err != nil {
+			return content, err
+		}
+		if index > len(content) {
+			return content, m.indexOutOfRange(index, len(content))
+		}
+	}
+	var values []any
+	if block, ok := m.value.(*insertValue); ok {
+		values = internal.Normalize(block.values).([]any)
+	} else {
+		values = []any{m.applyValue(nil)}
+	}
+	out := make([]any, 0, len(content)+len(values))
+	out = append(out, content[:index]...)
+	out = append(out, values...)
+	return append(out, content[index:]...), nil
+}
+
 func (m *Mutator) itemToArray(index int, content []any) ([]any, error) {
 	if m.child == nil {
 		switch m.operation {
 		case unsetOp:
 			return append(content[:index], content[index+1:]...), nil
+		case mergeOp:
+			merged, err := m.merge(content[index])
+			if err != nil {
+				return content, err
+			}
+			content[index] = merged
+			return content, nil
+		case replaceOp:
+			content[index] = m.applyValue(content[index])
+			return content, nil
+		case testOp:
+			if !internal.Equal(content[index], m.value) {
+				return content, testFailed(m.itemLocation(index).path, m.value, content[index])
+			}
+			return content, nil
 		case applyOp:
-			fmt.Println("ApplyOp")
-			if m.value != nil && index < len(content) {
-				content[index] = m.applyValue(content[index])
+			value, err := m.apply(m.itemLocation(index).path, content[index])
+			if err != nil {
+				return content, err
 			}
+			content[index] = value
+			return content, nil
+		case applyCtxOp:
+			content[index] = m.applyCtx(m.itemLocation(index), content, content[index])
 			return content, nil
 		default:
 			if m.value != nil && index < len(content) {
@@ -170,16 +437,35 @@ func (m *Mutator) itemToArray(index int, content []any) ([]any, error) {
 			return content, nil
 		}
 	}
-	if m.child.IsArray() {
-		child := castOrCreateArray(content[index])
-		c, err := m.Child().ToArray(child)
+	child := m.Child()
+	child.loc = m.itemLocation(index)
+	if child.recursive {
+		value, err := child.descend(content[index])
+		if err != nil {
+			return nil, err
+		}
+		content[index] = value
+		return content, nil
+	}
+	if content[index] == nil {
+		if m.child.fansOut() {
+			return content, nil
+		}
+		switch m.operation {
+		case unsetOp, applyOp:
+			return content, nil
+		case testOp:
+			return content, notFound(m.itemLocation(index).path)
+		}
+	}
+	if child.createsArray(content[index]) {
+		c, err := child.ToArray(castOrCreateArray(content[index]))
 		if err != nil {
 			return nil, err
 		}
 		content[index] = c
 	} else {
-		child := castOrCreateMap(content[index])
-		mapValue, mapErr := m.Child().ToMap(child)
+		mapValue, mapErr := child.ToMap(castOrCreateMap(content[index]))
 		if mapErr != nil {
 			return nil, mapErr
 		}
@@ -188,6 +474,77 @@ func (m *Mutator) itemToArray(index int, content []any) ([]any, error) {
 	return content, nil
 }
 
+func (m *Mutator) IsMultiple() bool {
+	if m.isRange() || m.fansOut() {
+		return true
+	}
+	return m.child != nil && m.child.IsMultiple()
+}
+
+func (m *Mutator) Lookup(content any) []any {
+	if m.isSelf() {
+		return []any{content}
+	}
+	if m.recursive {
+		return m.lookupDescendants(content)
+	}
+	var nodes []any
+	switch items := content.(type) {
+	case []any:
+		if !m.IsArray() {
+			return nil
+		}
+		if m.isRange() {
+			indexes, err := m.rangeIndexes(items)
+			if err != nil {
+				return nil
+			}
+			for _, index := range indexes {
+				nodes = append(nodes, items[index])
+			}
+		} else {
+			index, err := m.resolveIndex(len(items))
+			if err != nil || index >= len(items) {
+				return nil
+			}
+			nodes = items[index : index+1]
+		}
+	case map[string]any:
+		if m.isWildcard() {
+			for _, key := range sortedKeys(items) {
+				nodes = append(nodes, items[key])
+			}
+			break
+		}
+		if m.name == "" && !m.literal {
+			return nil
+		}
+		value, found := items[m.name]
+		if !found {
+			return nil
+		}
+		nodes = []any{value}
+	default:
+		return nil
+	}
+	if m.child == nil {
+		return nodes
+	}
+	var values []any
+	for _, node := range nodes {
+		values = append(values, m.child.Lookup(node)...)
+	}
+	return values
+}
+
+func (m *Mutator) clone() *Mutator {
+	c := *m
+	if m.child != nil {
+		c.child = m.child.clone()
+	}
+	return &c
+}
+
 func ensureSizeOfArray(arrayContent []any, indexStr string) []any {
 	index, err := strconv.Atoi(indexStr)
 	if err != nil {
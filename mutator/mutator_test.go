@@ -1,9 +1,12 @@
 package mutator
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ivancorrales/knoa/internal"
 )
 
 func Test_ensureSizeOfArray(t *testing.T) {
@@ -330,3 +333,201 @@ func Test_mutator_withValue(t *testing.T) {
 		})
 	}
 }
+
+func Test_mutator_lookup(t *testing.T) {
+	content := map[string]any{
+		"firstname": "Jane",
+		"siblings": []any{
+			map[string]any{"firstname": "Tim", "age": 29},
+			map[string]any{"firstname": "Bob", "age": 40},
+		},
+	}
+	tests := []struct {
+		name    string
+		mutator *Mutator
+		want    []any
+	}{
+		{
+			name:    "Lookup a single attribute",
+			mutator: &Mutator{name: "firstname"},
+			want:    []any{"Jane"},
+		},
+		{
+			name: "Lookup an item of an array",
+			mutator: &Mutator{
+				name: "siblings",
+				child: &Mutator{
+					index: "1",
+					child: &Mutator{name: "age"},
+				},
+			},
+			want: []any{40},
+		},
+		{
+			name: "Lookup all the items of an array",
+			mutator: &Mutator{
+				name: "siblings",
+				child: &Mutator{
+					index: "*",
+					child: &Mutator{name: "firstname"},
+				},
+			},
+			want: []any{"Tim", "Bob"},
+		},
+		{
+			name: "Lookup an index out of range",
+			mutator: &Mutator{
+				name:  "siblings",
+				child: &Mutator{index: "4"},
+			},
+			want: nil,
+		},
+		{
+			name:    "Lookup an attribute that doesn't exist",
+			mutator: &Mutator{name: "lastname"},
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equalf(t, tt.want, tt.mutator.Lookup(content), "Lookup(%v)", content)
+		})
+	}
+}
+
+func Test_mutator_newItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutator *Mutator
+		content []any
+		want    []any
+	}{
+		{
+			name:    "Append an item",
+			mutator: &Mutator{index: appendIndex, value: "c"},
+			content: []any{"a", "b"},
+			want:    []any{"a", "b", "c"},
+		},
+		{
+			name:    "Append an item with the JSON Pointer token",
+			mutator: &Mutator{index: pointerAppendIndex, value: "c"},
+			content: nil,
+			want:    []any{"c"},
+		},
+		{
+			name:    "Prepend an item",
+			mutator: &Mutator{index: prependIndex, value: "c"},
+			content: []any{"a", "b"},
+			want:    []any{"c", "a", "b"},
+		},
+		{
+			name: "Append an item into a nested array",
+			mutator: &Mutator{
+				index: "0",
+				child: &Mutator{
+					name:  "tags",
+					child: &Mutator{index: appendIndex, value: "y"},
+				},
+			},
+			content: []any{map[string]any{"tags": []any{"x"}}},
+			want:    []any{map[string]any{"tags": []any{"x", "y"}}},
+		},
+		{
+			name:    "Insert an item shifting the existing ones",
+			mutator: &Mutator{index: "1", value: "c", operation: insertOp},
+			content: []any{"a", "b"},
+			want:    []any{"a", "c", "b"},
+		},
+		{
+			name:    "Insert a block of items before the last one",
+			mutator: &Mutator{index: "-1", value: &insertValue{values: []any{"x", "y"}}, operation: insertOp},
+			content: []any{"a", "b"},
+			want:    []any{"a", "x", "y", "b"},
+		},
+		{
+			name:    "Unset doesn't append any item",
+			mutator: &Mutator{index: appendIndex, operation: unsetOp},
+			content: []any{"a"},
+			want:    []any{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.mutator.ToArray(tt.content)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_operation_insert(t *testing.T) {
+	tests := []struct {
+		name    string
+		syntax  PathSyntax
+		path    string
+		index   int
+		values  []any
+		want    any
+		patch   string
+		wantErr bool
+	}{
+		{
+			name:   "Insert in the middle",
+			path:   "items",
+			index:  1,
+			values: []any{"a", "b"},
+			want:   map[string]any{"items": []any{1, "a", "b", 2, 3}},
+			patch:  `[{"op":"add","path":"/items/1","value":"a"},{"op":"add","path":"/items/2","value":"b"}]`,
+		},
+		{
+			name:   "Insert before the last item",
+			path:   "items",
+			index:  -1,
+			values: []any{"a", "b"},
+			want:   map[string]any{"items": []any{1, 2, "a", "b", 3}},
+			patch:  `[{"op":"add","path":"/items/2","value":"a"},{"op":"add","path":"/items/3","value":"b"}]`,
+		},
+		{
+			name:   "Insert at the end",
+			path:   "items",
+			index:  3,
+			values: []any{"a"},
+			want:   map[string]any{"items": []any{1, 2, 3, "a"}},
+			patch:  `[{"op":"add","path":"/items/3","value":"a"}]`,
+		},
+		{
+			name:   "Insert with the JSON Pointer syntax",
+			syntax: PointerSyntax,
+			path:   "/items",
+			index:  0,
+			values: []any{"a"},
+			want:   map[string]any{"items": []any{"a", 1, 2, 3}},
+			patch:  `[{"op":"add","path":"/items/0","value":"a"}]`,
+		},
+		{
+			name:    "Insert out of range",
+			path:    "items",
+			index:   -4,
+			values:  []any{"a"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutators, err := NewOperation().Insert(&Parser{Syntax: tt.syntax}, tt.path, tt.index, tt.values)
+			assert.NoError(t, err)
+			content := map[string]any{"items": []any{1, 2, 3}}
+			got, err := Mutate(internal.Normalize(content), mutators[0])
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			patch, err := NewPatch(content, mutators)
+			assert.NoError(t, err)
+			out, _ := json.Marshal(patch)
+			assert.JSONEq(t, tt.patch, string(out))
+		})
+	}
+}
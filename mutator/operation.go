@@ -2,10 +2,11 @@ package mutator
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
 
-	"github.com/fatih/structs"
-
+	"github.com/ivancorrales/knoa/internal"
 	"github.com/ivancorrales/knoa/sanitizer"
 )
 
@@ -15,6 +16,15 @@ const (
 	setOp operationCode = iota
 	unsetOp
 	applyOp
+	mergeOp
+	insertOp
+	replaceOp
+	testOp
+	moveOp
+	copyOp
+	patchOp
+	applyCtxOp
+	restoreOp
 )
 
 type operation struct {
@@ -44,17 +54,34 @@ func NewOperation(opts ...OperationOpt) *operation {
 	return op
 }
 
+func (op *operation) path(path string) string {
+	if op.prefix != "" {
+		path = op.prefix + path
+	}
+	if op.funcPrefix != nil {
+		path = op.funcPrefix(path)
+	}
+	return path
+}
+
+// parse applies the prefixes to path expressions. Compiled paths are taken as they are, unless there are prefixes.
+func (op *operation) parse(parser *Parser, path sanitizer.Path) (*Mutator, error) {
+	if op.prefix == "" && op.funcPrefix == nil {
+		return parser.ParsePath(path)
+	}
+	switch v := path.(type) {
+	case string:
+		return parser.Parse(op.path(v))
+	case Path:
+		return parser.Parse(op.path(v.expr))
+	}
+	return parser.ParsePath(path)
+}
+
 func (op *operation) Set(parser *Parser, pathValueList sanitizer.PathValueList) (mutators []Mutator, outErr error) {
 	for _, pathValue := range pathValueList {
 		v := op.checkValue(pathValue.Value)
-		path := pathValue.Path
-		if op.prefix != "" {
-			path = op.prefix + path
-		}
-		if op.funcPrefix != nil {
-			path = op.funcPrefix(path)
-		}
-		m, err := parser.Parse(path)
+		m, err := op.parse(parser, pathValue.Path)
 		if err != nil {
 			outErr = errors.Join(outErr, err)
 		}
@@ -66,15 +93,9 @@ func (op *operation) Set(parser *Parser, pathValueList sanitizer.PathValueList)
 	return
 }
 
-func (op *operation) Unset(parser *Parser, paths []string) (mutators []Mutator, outErr error) {
+func (op *operation) Unset(parser *Parser, paths []sanitizer.Path) (mutators []Mutator, outErr error) {
 	for _, path := range paths {
-		if op.prefix != "" {
-			path = op.prefix + path
-		}
-		if op.funcPrefix != nil {
-			path = op.funcPrefix(path)
-		}
-		m, err := parser.Parse(path)
+		m, err := op.parse(parser, path)
 		if err != nil {
 			outErr = errors.Join(outErr, err)
 		}
@@ -86,33 +107,180 @@ func (op *operation) Unset(parser *Parser, paths []string) (mutators []Mutator,
 	return
 }
 
+// Apply transforms the values in the paths with the funcs. The paths that aren't in the document are skipped.
 func (op *operation) Apply(parser *Parser, patchFuncList sanitizer.PathFuncList) (mutators []Mutator, outErr error) {
 	for _, pathFunc := range patchFuncList {
-		path := pathFunc.Path
-		if op.prefix != "" {
-			path = op.prefix + path
-		}
-		if op.funcPrefix != nil {
-			path = op.funcPrefix(path)
-		}
-		m, err := parser.Parse(path)
+		m, err := op.parse(parser, pathFunc.Path)
 		if err != nil {
 			outErr = errors.Join(outErr, err)
 		}
+		f, fnErr := newApplyFunc(pathFunc.Func)
+		if fnErr != nil {
+			outErr = errors.Join(outErr, &ApplyError{Path: fmt.Sprint(pathFunc.Path), Err: fnErr})
+			continue
+		}
 		if m != nil {
 			m.operation = applyOp
-			m.addValueToNode(pathFunc.Func)
+			m.addValueToNode(f)
 			mutators = append(mutators, *m)
 		}
 	}
 	return
 }
 
+func (op *operation) ApplyCtx(parser *Parser, path sanitizer.Path, fn func(ctx NodeContext) any) ([]Mutator, error) {
+	if fn == nil {
+		return nil, &ApplyError{Path: fmt.Sprint(path), Err: errors.New("invalid func")}
+	}
+	m, err := op.parse(parser, path)
+	if err != nil || m == nil {
+		return nil, err
+	}
+	m.operation = applyCtxOp
+	m.addValueToNode(fn)
+	return []Mutator{*m}, nil
+}
+
+func (op *operation) Merge(content any, opts ...MergeOpt) []Mutator {
+	return []Mutator{
+		{
+			operation: mergeOp,
+			child: &Mutator{
+				value: &mergeValue{
+					content: internal.Normalize(content),
+					merger:  NewMerger(opts...),
+				},
+			},
+		},
+	}
+}
+
+func (op *operation) Insert(parser *Parser, path string, index int, values []any) ([]Mutator, error) {
+	m := &Mutator{child: &Mutator{}}
+	if expr := op.path(path); expr != "" {
+		var err error
+		if m, err = parser.Parse(expr); err != nil || m == nil {
+			return nil, err
+		}
+	}
+	// the index is attached to the parsed path, so it doesn't depend on the path syntax
+	leaf := m.child
+	for leaf.child != nil {
+		leaf = leaf.child
+	}
+	if leaf.isSelf() {
+		leaf.index = strconv.Itoa(index)
+	} else {
+		leaf.child = &Mutator{index: strconv.Itoa(index)}
+	}
+	block := &insertValue{values: make([]any, len(values))}
+	for i, value := range values {
+		block.values[i] = op.checkValue(value)
+	}
+	m.operation = insertOp
+	m.addValueToNode(block)
+	return []Mutator{*m}, nil
+}
+
+func (op *operation) Move(parser *Parser, from, to string) ([]Mutator, error) {
+	return op.transfer(parser, moveOp, from, to)
+}
+
+func (op *operation) Copy(parser *Parser, from, to string) ([]Mutator, error) {
+	return op.transfer(parser, copyOp, from, to)
+}
+
+func (op *operation) Rename(parser *Parser, path, name string) ([]Mutator, error) {
+	from, err := parser.Parse(op.path(path))
+	if err != nil || from == nil {
+		return nil, err
+	}
+	to := from.clone()
+	leaf := to
+	for leaf.child != nil {
+		leaf = leaf.child
+	}
+	if leaf.name == "" && !leaf.literal {
+		return nil, &PathError{Path: path, Reason: "only attributes can be renamed"}
+	}
+	leaf.name = name
+	return []Mutator{newTransfer(moveOp, from, to, path, name)}, nil
+}
+
+func (op *operation) transfer(parser *Parser, operation operationCode, fromPath, toPath string) ([]Mutator, error) {
+	from, err := parser.Parse(op.path(fromPath))
+	if err != nil || from == nil {
+		return nil, err
+	}
+	to, err := parser.Parse(op.path(toPath))
+	if err != nil || to == nil {
+		return nil, err
+	}
+	if from.hasDescent() || to.hasDescent() {
+		path := fromPath
+		if to.hasDescent() {
+			path = toPath
+		}
+		return nil, &PathError{Path: path, Reason: "recursive descent is not supported"}
+	}
+	return []Mutator{newTransfer(operation, from, to, fromPath, toPath)}, nil
+}
+
+func newTransfer(operation operationCode, from, to *Mutator, fromPath, toPath string) Mutator {
+	return Mutator{
+		operation: operation,
+		child: &Mutator{
+			value: &transferValue{
+				from:     from,
+				to:       to,
+				fromPath: fromPath,
+				toPath:   toPath,
+				targetOp: replaceOp,
+			},
+		},
+	}
+}
+
+// Restore replaces the whole document with the given content.
+func (op *operation) Restore(content any) []Mutator {
+	return []Mutator{
+		{
+			operation: restoreOp,
+			child:     &Mutator{value: internal.Normalize(content)},
+		},
+	}
+}
+
+func (op *operation) MergePatch(patch map[string]any) []Mutator {
+	return op.Merge(patch, withMergePatch())
+}
+
+func (op *operation) Patch(patch Patch) ([]Mutator, error) {
+	mutators := make([]Mutator, len(patch))
+	for i, o := range patch {
+		m, err := o.mutator()
+		if err != nil {
+			return nil, err
+		}
+		mutators[i] = *m
+	}
+	return []Mutator{
+		{
+			operation: patchOp,
+			child: &Mutator{
+				value: &patchValue{
+					patch:    patch,
+					mutators: mutators,
+				},
+			},
+		},
+	}, nil
+}
+
 func (op *operation) checkValue(value any) any {
 	switch reflect.ValueOf(value).Kind() {
 	case reflect.Struct:
-		mapValues := structs.Map(value)
-		return mapValues
+		return internal.Normalize(value)
 	default:
 		return value
 	}
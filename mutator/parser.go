@@ -2,101 +2,224 @@ package mutator
 
 import (
 	"fmt"
-	"log"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 const (
 	DefAttributeNameFormat = `("[A-Za-z_]+[A-Za-z0-9_./-]*"|[A-Za-z_]+[A-Za-z0-9_/-]*)`
-	arrayIndexExprStr      = `([0-9]+|\*)`
+	arrayIndexExprStr      = `(\?\((?:[^\[\]]|\[[^\[\]]*\])*\)|-?[0-9]*:-?[0-9]*(?::-?[0-9]*)?|-?[0-9]+|\*|\+|-|\^)`
+)
+
+var arrayIndexRegExp = regexp.MustCompile(`^(-?[0-9]*:-?[0-9]*(:-?[0-9]*)?|-?[0-9]+|\*|\+|-|\^)$`)
+
+type PathSyntax int32
+
+const (
+	DottedSyntax PathSyntax = iota
+	PointerSyntax
 )
 
 type Parser struct {
-	RegExp          *regexp.Regexp
+	// Deprecated: paths are tokenized instead of being matched against a regular expression.
+	RegExp *regexp.Regexp
+	// AttributeRegExp validates the unquoted attribute names.
 	AttributeRegExp *regexp.Regexp
-	Strict          bool
+	// Deprecated: it has no effect, invalid paths are always reported with a PathError instead of panicking.
+	Strict bool
+	Syntax PathSyntax
+
+	cacheOnce sync.Once
+	cache     *pathCache
+}
+
+// Path is a path expression parsed in advance, that can be used as many times as needed.
+type Path struct {
+	expr string
+	root *Mutator
+}
+
+func (p Path) String() string {
+	return p.expr
+}
+
+// Expr returns the path expression the path was compiled from.
+func (p Path) Expr() string {
+	return p.expr
+}
+
+func (p *Parser) Compile(pathExpr string) (Path, error) {
+	root, err := p.cached(pathExpr)
+	if err != nil {
+		return Path{}, err
+	}
+	return Path{expr: pathExpr, root: root}, nil
+}
+
+// ParsePath accepts either a path expression or a compiled Path.
+func (p *Parser) ParsePath(path any) (*Mutator, error) {
+	switch v := path.(type) {
+	case Path:
+		if v.root == nil {
+			return nil, &PathError{Path: v.expr, Reason: "empty path"}
+		}
+		return v.root.clone(), nil
+	case string:
+		return p.Parse(v)
+	}
+	return nil, &PathError{Path: fmt.Sprint(path), Reason: "paths must be strings"}
 }
 
 func RegExpFromAttributeFormat(attributeFormat string) *regexp.Regexp {
-	regExpStr := fmt.Sprintf(`^(?P<parent>(((\.)?%s|\[%s\]))*)((\.)(?P<attribute>%s)|(\[(?P<index>%s)\]))$`,
-		attributeFormat, arrayIndexExprStr, attributeFormat, arrayIndexExprStr)
-	return regexp.MustCompile(regExpStr)
+	pathRegExp, _ := RegExpsFromAttributeFormat(attributeFormat)
+	return pathRegExp
 }
 
 func RegExpsFromAttributeFormat(attributeFormat string) (*regexp.Regexp, *regexp.Regexp) {
-	regExpStr := fmt.Sprintf(`^(?P<parent>(((\.)?%s|\[%s\]))*)((\.)(?P<attribute>%s)|(\[(?P<index>%s)\]))$`,
-		attributeFormat, arrayIndexExprStr, attributeFormat, arrayIndexExprStr)
+	pathFormat := fmt.Sprintf(`(%s|\*)`, attributeFormat)
+	regExpStr := fmt.Sprintf(`^(?P<parent>(((\.\.?)?%s|\[%s\]))*)((?P<separator>\.\.?)(?P<attribute>%s)|(\[(?P<index>%s)\]))$`,
+		pathFormat, arrayIndexExprStr, pathFormat, arrayIndexExprStr)
+
+	return regexp.MustCompile(regExpStr), AttributeRegExpFromFormat(attributeFormat)
+}
 
-	return regexp.MustCompile(regExpStr), regexp.MustCompile(fmt.Sprintf(`^(?P<attribute>%s)$`, attributeFormat))
+var defAttributeRegExp = regexp.MustCompile(attributeExpr(DefAttributeNameFormat))
+
+// AttributeRegExpFromFormat builds the regular expression that validates the unquoted attribute names.
+func AttributeRegExpFromFormat(attributeFormat string) *regexp.Regexp {
+	if attributeFormat == DefAttributeNameFormat {
+		return defAttributeRegExp
+	}
+	return regexp.MustCompile(attributeExpr(attributeFormat))
+}
+
+func attributeExpr(attributeFormat string) string {
+	return fmt.Sprintf(`^(?P<attribute>(%s|\*))$`, attributeFormat)
 }
 
 func (p *Parser) Parse(pathExpr string) (*Mutator, error) {
-	match := p.RegExp.FindStringSubmatch(pathExpr)
-	if match == nil {
-		attrMatch := p.AttributeRegExp.FindStringSubmatch(pathExpr)
-		if attrMatch != nil {
-			return &Mutator{
-				child: &Mutator{
-					name: pathExpr,
-				},
-			}, nil
-		}
-		if p.Strict {
-			log.Panicf("invalid Path  '%v'. Path doesn't match defined format", pathExpr)
-		}
-		return nil, fmt.Errorf("invalid path '%s'", pathExpr)
+	root, err := p.cached(pathExpr)
+	if err != nil {
+		return nil, err
 	}
-	subMatchMap := map[string]string{}
-	for i, name := range p.RegExp.SubexpNames() {
-		if i != 0 {
-			subMatchMap[name] = match[i]
-		}
+	return root.clone(), nil
+}
+
+func (p *Parser) cached(pathExpr string) (*Mutator, error) {
+	p.cacheOnce.Do(func() {
+		p.cache = newPathCache(defaultPathCacheSize)
+	})
+	if root, found := p.cache.get(pathExpr); found {
+		return root, nil
 	}
-	attr := subMatchMap["attribute"]
-	if strings.HasPrefix(attr, ".") {
-		attr = attr[1:]
+	root, err := p.parse(pathExpr)
+	if err != nil {
+		return nil, err
 	}
-	parentExpr := subMatchMap["parent"]
-	if strings.HasSuffix(parentExpr, ".") {
-		parentExpr = parentExpr[:len(parentExpr)-1]
+	p.cache.add(pathExpr, root)
+	return root, nil
+}
+
+func (p *Parser) parse(pathExpr string) (*Mutator, error) {
+	if p.Syntax == PointerSyntax {
+		return ParsePointer(pathExpr)
 	}
-	arrayIndex := subMatchMap["index"]
-	m := &Mutator{
-		name: attr,
+	segments, err := parseSegments(pathExpr)
+	if err != nil {
+		return nil, err
 	}
-	if arrayIndex != "" {
-		m.index = arrayIndex
-		parent := &Mutator{}
-		var err error
-		if parentExpr != "" {
-			parent, err = p.Parse(parentExpr)
-			if parent == nil {
-				parent = &Mutator{
-					name: parentExpr,
-				}
+	root := &Mutator{}
+	for _, s := range segments {
+		if s.bare && s.name != wildcardIndex && p.AttributeRegExp != nil && !p.AttributeRegExp.MatchString(s.name) {
+			return nil, &PathError{Path: pathExpr, Column: s.column, Reason: fmt.Sprintf("attribute '%s' doesn't match the defined format", s.name)}
+		}
+		m := &Mutator{name: s.name, index: s.index, recursive: s.recursive, literal: s.index == "" && !s.bare}
+		if isFilter(s.index) {
+			if m.filter, err = parseFilter(s.index); err != nil {
+				return nil, &PathError{Path: pathExpr, Column: s.column, Reason: err.Error()}
 			}
 		}
-		addToBottom(parent, m)
-		return parent, err
+		addToBottom(root, m)
 	}
-	if parentExpr != "" {
-		if attr != "" {
-			if attr[0] == '"' && attr[len(attr)-1] == '"' {
-				m.name = attr[1 : len(attr)-1]
+	return root, nil
+}
+
+// segment is a node of the parsed path: either an attribute or an array index.
+type segment struct {
+	name      string
+	index     string
+	recursive bool
+	// bare is set for the attribute names that were not quoted.
+	bare   bool
+	column int
+}
+
+func parseSegments(pathExpr string) ([]segment, error) {
+	tokens, err := tokenize(pathExpr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, &PathError{Path: pathExpr, Reason: "empty path"}
+	}
+	fail := func(column int, format string, args ...any) error {
+		return &PathError{Path: pathExpr, Column: column, Reason: fmt.Sprintf(format, args...)}
+	}
+	var segments []segment
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch t.kind {
+		case bracketToken:
+			s, bracketErr := bracketSegment(t)
+			if bracketErr != nil {
+				return nil, fail(t.column, "%v", bracketErr)
 			}
-		}
-		var err error
-		parent, err := p.Parse(parentExpr)
-		if parent == nil {
-			parent = &Mutator{
-				name: parentExpr,
+			segments = append(segments, s)
+			continue
+		case nameToken, quotedToken:
+			if i > 0 {
+				return nil, fail(t.column, "expected '.' or '[' before '%s'", t.text)
 			}
+			segments = append(segments, segment{name: t.text, bare: t.kind == nameToken, column: t.column})
+			continue
+		}
+		if i+1 >= len(tokens) {
+			return nil, fail(t.column, "missing attribute name after '%s'", t.text)
+		}
+		next := tokens[i+1]
+		if next.kind != nameToken && next.kind != quotedToken {
+			return nil, fail(next.column, "expected an attribute name after '%s'", t.text)
+		}
+		segments = append(segments, segment{
+			name:      next.text,
+			bare:      next.kind == nameToken,
+			recursive: t.kind == descentToken,
+			column:    next.column,
+		})
+		i++
+	}
+	return segments, nil
+}
+
+func bracketSegment(t token) (segment, error) {
+	text := strings.TrimSpace(t.text)
+	s := segment{column: t.column}
+	switch {
+	case text == "":
+		return s, fmt.Errorf("empty brackets")
+	case text[0] == '"' || text[0] == '\'':
+		tokens, err := tokenize(text)
+		if err != nil || len(tokens) != 1 || tokens[0].kind != quotedToken {
+			return s, fmt.Errorf("invalid quoted key %s", text)
 		}
-		addToBottom(parent, m)
-		return parent, err
+		s.name = tokens[0].text
+	case isFilter(text), arrayIndexRegExp.MatchString(text):
+		s.index = text
+	default:
+		return s, fmt.Errorf("invalid index '%s'", text)
 	}
-	return m, nil
+	return s, nil
 }
 
 func addToBottom(parent *Mutator, child *Mutator) {
@@ -15,11 +15,11 @@ func Test_parser_parse(t *testing.T) {
 		pathExpr string
 	}
 	tests := []struct {
-		name     string
-		fields   fields
-		args     args
-		want     *Mutator
-		panicked bool
+		name    string
+		fields  fields
+		args    args
+		want    *Mutator
+		wantErr bool
 	}{
 		{
 			name: "A single attribute name",
@@ -79,7 +79,7 @@ func Test_parser_parse(t *testing.T) {
 			args: args{
 				pathExpr: "peopl\\\\e[0].firstname",
 			},
-			want: nil,
+			wantErr: true,
 		},
 		{
 			name: "An invalid expression and Strict mode is enabled",
@@ -89,8 +89,7 @@ func Test_parser_parse(t *testing.T) {
 			args: args{
 				pathExpr: "peopl\\\\e[0].firstname",
 			},
-			want:     nil,
-			panicked: true,
+			wantErr: true,
 		},
 		{
 			name: "A simple Array",
@@ -144,6 +143,117 @@ func Test_parser_parse(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Append and prepend items",
+			fields: fields{
+				strict: true,
+			},
+			args: args{
+				pathExpr: "items[+].tags[^]",
+			},
+			want: &Mutator{
+				child: &Mutator{
+					name: "items",
+					child: &Mutator{
+						index: "+",
+						child: &Mutator{
+							name: "tags",
+							child: &Mutator{
+								index: "^",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Negative index and slice",
+			fields: fields{
+				strict: true,
+			},
+			args: args{
+				pathExpr: "items[-1].tags[1:3]",
+			},
+			want: &Mutator{
+				child: &Mutator{
+					name: "items",
+					child: &Mutator{
+						index: "-1",
+						child: &Mutator{
+							name: "tags",
+							child: &Mutator{
+								index: "1:3",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Filter predicate",
+			fields: fields{
+				strict: true,
+			},
+			args: args{
+				pathExpr: "users[?(@.age > 30 && @.tags[0] == \"admin\")].active",
+			},
+			want: &Mutator{
+				child: &Mutator{
+					name: "users",
+					child: &Mutator{
+						index: "?(@.age > 30 && @.tags[0] == \"admin\")",
+						child: &Mutator{
+							name: "active",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid filter predicate",
+			fields: fields{
+				strict: true,
+			},
+			args: args{
+				pathExpr: "users[?(@.age >)].active",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Map wildcard",
+			fields: fields{
+				strict: true,
+			},
+			args: args{
+				pathExpr: "services.*.replicas",
+			},
+			want: &Mutator{
+				child: &Mutator{
+					name: "services",
+					child: &Mutator{
+						name: "*",
+						child: &Mutator{
+							name: "replicas",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Recursive descent",
+			fields: fields{
+				strict: true,
+			},
+			args: args{
+				pathExpr: "..password",
+			},
+			want: &Mutator{
+				child: &Mutator{
+					name:      "password",
+					recursive: true,
+				},
+			},
+		},
 		{
 			name: "Attributes contains dots ",
 			fields: fields{
@@ -202,12 +312,15 @@ func Test_parser_parse(t *testing.T) {
 				AttributeRegExp: attrRegExp,
 			}
 
-			if tt.panicked {
-				assert.Panics(t, func() { p.Parse(tt.args.pathExpr) }, "The execution should end panicking")
-			} else {
-				res, _ := p.Parse(tt.args.pathExpr)
-				assertParsedElements(t, tt.want, res)
+			res, err := p.Parse(tt.args.pathExpr)
+			if tt.wantErr {
+				var pathErr *PathError
+				assert.ErrorAs(t, err, &pathErr)
+				assert.Nil(t, res)
+				return
 			}
+			assert.NoError(t, err)
+			assertParsedElements(t, tt.want, res)
 		})
 	}
 }
@@ -251,3 +364,167 @@ func Test_RegExpFromAttributeFormat(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		pathExpr string
+		want     []segment
+		wantErr  string
+	}{
+		{
+			name:     "Quoted key between brackets",
+			pathExpr: `metadata["a.b"]`,
+			want: []segment{
+				{name: "metadata", bare: true, column: 1},
+				{name: "a.b", column: 9},
+			},
+		},
+		{
+			name:     "Single quoted key with spaces and unicode",
+			pathExpr: `'key with spaces'.ñandú`,
+			want: []segment{
+				{name: "key with spaces", column: 1},
+				{name: "ñandú", bare: true, column: 19},
+			},
+		},
+		{
+			name:     "Backslash escapes",
+			pathExpr: `a\.b."say \"hi\""[0]`,
+			want: []segment{
+				{name: "a.b", column: 1},
+				{name: `say "hi"`, column: 6},
+				{index: "0", column: 18},
+			},
+		},
+		{
+			name:     "Escaped characters that are not part of the syntax",
+			pathExpr: `a\-b.\\`,
+			want: []segment{
+				{name: "a-b", bare: true, column: 1},
+				{name: `\`, bare: true, column: 6},
+			},
+		},
+		{
+			name:     "Escaped wildcard",
+			pathExpr: `items.\*`,
+			want: []segment{
+				{name: "items", bare: true, column: 1},
+				{name: "*", column: 7},
+			},
+		},
+		{
+			name:     "Recursive descent and wildcards",
+			pathExpr: `..items[*].*`,
+			want: []segment{
+				{name: "items", bare: true, recursive: true, column: 3},
+				{index: "*", column: 8},
+				{name: "*", bare: true, column: 12},
+			},
+		},
+		{
+			name:     "Filter with brackets and quotes",
+			pathExpr: `users[?(@.tags[0] == "]")].name`,
+			want: []segment{
+				{name: "users", bare: true, column: 1},
+				{index: `?(@.tags[0] == "]")`, column: 6},
+				{name: "name", bare: true, column: 28},
+			},
+		},
+		{
+			name:     "Empty path",
+			pathExpr: "",
+			wantErr:  "invalid path '': empty path",
+		},
+		{
+			name:     "Trailing dot",
+			pathExpr: "a.b.",
+			wantErr:  "invalid path 'a.b.': missing attribute name after '.' at column 4",
+		},
+		{
+			name:     "Missing closing bracket",
+			pathExpr: "a[0",
+			wantErr:  "invalid path 'a[0': missing ']' at column 2",
+		},
+		{
+			name:     "Invalid index",
+			pathExpr: "a[x]",
+			wantErr:  "invalid path 'a[x]': invalid index 'x' at column 2",
+		},
+		{
+			name:     "Unterminated quoted key",
+			pathExpr: `a."b`,
+			wantErr:  `invalid path 'a."b': unterminated quoted key at column 3`,
+		},
+		{
+			name:     "Missing dot between attributes",
+			pathExpr: `a[0]b`,
+			wantErr:  `invalid path 'a[0]b': expected '.' or '[' before 'b' at column 5`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSegments(tt.pathExpr)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parser_attributeFormat(t *testing.T) {
+	p := &Parser{AttributeRegExp: AttributeRegExpFromFormat("[a-z]+")}
+	_, err := p.Parse("spec.Image")
+	assert.EqualError(t, err, "invalid path 'spec.Image': attribute 'Image' doesn't match the defined format at column 6")
+	m, err := p.Parse(`spec."Image"`)
+	assert.NoError(t, err)
+	assert.Equal(t, "Image", m.child.child.name)
+	assert.Same(t, AttributeRegExpFromFormat(DefAttributeNameFormat), AttributeRegExpFromFormat(DefAttributeNameFormat))
+}
+
+func Test_parser_escapedNames(t *testing.T) {
+	p := &Parser{AttributeRegExp: AttributeRegExpFromFormat(DefAttributeNameFormat)}
+	m, err := p.Parse(`metadata.app\.kubernetes\.io`)
+	assert.NoError(t, err)
+	assert.Equal(t, "app.kubernetes.io", m.child.child.name)
+	assert.True(t, m.child.child.literal)
+	_, err = p.Parse(`metadata.app\-name`)
+	assert.NoError(t, err)
+	_, err = p.Parse(`metadata.app\\name`)
+	assert.EqualError(t, err, `invalid path 'metadata.app\\name': attribute 'app\name' doesn't match the defined format at column 10`)
+}
+
+func Test_parser_pointerSyntax(t *testing.T) {
+	_, attrRegExp := RegExpsFromAttributeFormat(DefAttributeNameFormat)
+	dotted := &Parser{AttributeRegExp: attrRegExp}
+	pointer := &Parser{AttributeRegExp: attrRegExp, Syntax: PointerSyntax}
+	tests := []struct {
+		name    string
+		pointer string
+		dotted  string
+	}{
+		{name: "Attributes", pointer: "/spec/image", dotted: "spec.image"},
+		{name: "Array items", pointer: "/spec/containers/0/image", dotted: "spec.containers[0].image"},
+		{name: "Append token", pointer: "/spec/args/-", dotted: "spec.args[+]"},
+		{name: "Escaped tokens", pointer: "/metadata/a~1b/~0c", dotted: `metadata."a/b"["~c"]`},
+		{name: "Wildcards are literal", pointer: "/metadata/*", dotted: `metadata["*"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pointer.Parse(tt.pointer)
+			assert.NoError(t, err)
+			got.addValueToNode("value")
+			want, _ := dotted.Parse(tt.dotted)
+			want.addValueToNode("value")
+			gotContent, err := Mutate(nil, *got)
+			assert.NoError(t, err)
+			wantContent, _ := Mutate(nil, *want)
+			assert.Equal(t, wantContent, gotContent)
+		})
+	}
+	_, err := pointer.Parse("spec/image")
+	assert.Equal(t, &PathError{Path: "spec/image", Reason: "a JSON Pointer must start with '/'"}, err)
+}
@@ -0,0 +1,433 @@
+package mutator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ivancorrales/knoa/internal"
+)
+
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+	PatchMove    = "move"
+	PatchCopy    = "copy"
+	PatchTest    = "test"
+)
+
+// PatchOperation is an operation of a JSON Patch document as defined by RFC 6902.
+type PatchOperation struct {
+	Op    string
+	Path  string
+	From  string
+	Value any
+}
+
+type Patch []PatchOperation
+
+type patchOperationJSON struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	out := map[string]any{
+		"op":   o.Op,
+		"path": o.Path,
+	}
+	switch o.Op {
+	case PatchMove, PatchCopy:
+		out["from"] = o.From
+	case PatchAdd, PatchReplace, PatchTest:
+		out["value"] = o.Value
+	}
+	return json.Marshal(out)
+}
+
+func (o *PatchOperation) UnmarshalJSON(content []byte) error {
+	var in patchOperationJSON
+	if err := json.Unmarshal(content, &in); err != nil {
+		return err
+	}
+	if in.Path == nil {
+		return &PatchError{Op: in.Op, Reason: "missing path"}
+	}
+	o.Op, o.Path = in.Op, *in.Path
+	switch o.Op {
+	case PatchMove, PatchCopy:
+		if in.From == nil {
+			return &PatchError{Op: in.Op, Reason: "missing from"}
+		}
+		o.From = *in.From
+	case PatchAdd, PatchReplace, PatchTest:
+		if len(in.Value) == 0 {
+			return &PatchError{Op: in.Op, Reason: "missing value"}
+		}
+		if err := json.Unmarshal(in.Value, &o.Value); err != nil {
+			return err
+		}
+	case PatchRemove:
+	default:
+		return &PatchError{Op: in.Op, Reason: "unsupported operation"}
+	}
+	return nil
+}
+
+func ParsePatch(content []byte) (Patch, error) {
+	var patch Patch
+	if err := json.Unmarshal(content, &patch); err != nil {
+		return nil, err
+	}
+	return patch, nil
+}
+
+type patchValue struct {
+	patch    Patch
+	mutators []Mutator
+}
+
+func (o PatchOperation) mutator() (*Mutator, error) {
+	path, err := ParsePointer(o.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch o.Op {
+	case PatchAdd:
+		path.operation = insertOp
+		path.addValueToNode(internal.Normalize(o.Value))
+	case PatchRemove:
+		path.operation = unsetOp
+	case PatchReplace:
+		path.operation = replaceOp
+		path.addValueToNode(internal.Normalize(o.Value))
+	case PatchTest:
+		path.operation = testOp
+		path.addValueToNode(internal.Normalize(o.Value))
+	case PatchMove, PatchCopy:
+		from, fromErr := ParsePointer(o.From)
+		if fromErr != nil {
+			return nil, fromErr
+		}
+		operation := copyOp
+		if o.Op == PatchMove {
+			operation = moveOp
+		}
+		return &Mutator{
+			operation: operation,
+			child: &Mutator{
+				value: &transferValue{from: from, to: path, op: o},
+			},
+		}, nil
+	default:
+		return nil, &PatchError{Op: o.Op, Reason: "unsupported operation"}
+	}
+	return path, nil
+}
+
+// patch applies all the operations of the patch or none of them.
+func (m *Mutator) patch(content any) (any, error) {
+	v, ok := m.value.(*patchValue)
+	if !ok {
+		return content, fmt.Errorf("invalid patch value")
+	}
+	out := internal.Normalize(content)
+	for i := range v.mutators {
+		err := v.patch[i].check(v.mutators[i], out)
+		if err == nil {
+			out, err = Mutate(out, v.mutators[i])
+		}
+		if err != nil {
+			return content, fmt.Errorf("%s operation on '%s' failed: %w", v.patch[i].Op, v.patch[i].Path, err)
+		}
+	}
+	return out, nil
+}
+
+// check verifies that the operation can be applied to the content as RFC 6902 requires: the target
+// of remove and replace must exist, the parent of the target of add too, and a value can't be moved
+// into one of its children.
+func (o PatchOperation) check(m Mutator, content any) error {
+	switch o.Op {
+	case PatchRemove, PatchReplace:
+		if len(m.child.Lookup(content)) == 0 {
+			return notFound(o.Path)
+		}
+	case PatchAdd:
+		if i := strings.LastIndex(o.Path, "/"); i > 0 {
+			parent, err := ParsePointer(o.Path[:i])
+			if err != nil {
+				return err
+			}
+			if len(parent.child.Lookup(content)) == 0 {
+				return notFound(o.Path[:i])
+			}
+		}
+	case PatchMove:
+		if strings.HasPrefix(o.Path, o.From+"/") {
+			return &PathError{Path: o.Path, Reason: fmt.Sprintf("is inside '%s', that is moved", o.From)}
+		}
+	}
+	return nil
+}
+
+// ParsePointer translates a JSON Pointer (RFC 6901) into a chain of mutators. Tokens
+// that could be either an attribute or an array index are resolved against the content.
+func ParsePointer(pointer string) (*Mutator, error) {
+	root := &Mutator{}
+	if pointer == "" {
+		root.child = &Mutator{}
+		return root, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, &PathError{Path: pointer, Reason: "a JSON Pointer must start with '/'"}
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		node := &Mutator{name: token, literal: true}
+		if token == pointerAppendIndex || isPointerIndex(token) {
+			node.index = token
+		}
+		addToBottom(root, node)
+	}
+	return root, nil
+}
+
+func isPointerIndex(token string) bool {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return false
+	}
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pointer(tokens []string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteString("/")
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// NewPatch builds the JSON Patch that is equivalent to applying the mutators over the content.
+func NewPatch(content any, mutators []Mutator) (Patch, error) {
+	patch := Patch{}
+	doc := internal.Normalize(content)
+	var outErr error
+	for _, m := range mutators {
+		next, err := Mutate(internal.Normalize(doc), m)
+		if err != nil {
+			outErr = errors.Join(outErr, err)
+			continue
+		}
+		patch = append(patch, m.patchOperations(doc, next)...)
+		doc = next
+	}
+	return patch, outErr
+}
+
+func (m *Mutator) patchOperations(before, after any) Patch {
+	switch m.operation {
+	case patchOp:
+		if v, ok := m.child.value.(*patchValue); ok {
+			return v.patch
+		}
+		return nil
+	case moveOp, copyOp:
+		if v, ok := m.child.value.(*transferValue); ok {
+			return v.patchOperations(m.operation, before)
+		}
+		return nil
+	case mergeOp:
+		return Patch{{Op: PatchReplace, Path: "", Value: after}}
+	}
+	targets := m.child.targets(before)
+	var patch Patch
+	if m.operation == unsetOp {
+		for i := len(targets) - 1; i >= 0; i-- {
+			if _, found := valueAt(before, targets[i]); found {
+				patch = append(patch, PatchOperation{Op: PatchRemove, Path: pointer(targets[i])})
+			}
+		}
+		return patch
+	}
+	depth := m.child.newItemDepth()
+	for _, target := range targets {
+		if m.operation == insertOp {
+			patch = append(patch, m.insertOperations(before, after, target)...)
+			continue
+		}
+		if depth >= 0 {
+			// the array the item is added to must exist, or it's added with the item
+			if _, found := valueAt(before, target[:depth]); !found {
+				patch = append(patch, addedOrReplaced(before, after, target[:depth])...)
+				continue
+			}
+			value, _ := valueAt(after, target[:depth+1])
+			patch = append(patch, PatchOperation{Op: PatchAdd, Path: pointer(target[:depth+1]), Value: value})
+			continue
+		}
+		patch = append(patch, addedOrReplaced(before, after, target)...)
+	}
+	return patch
+}
+
+// insertOperations adds each of the values inserted at the target, which are placed one after the other.
+func (m *Mutator) insertOperations(before, after any, target []string) Patch {
+	count := 1
+	leaf := m
+	for leaf.child != nil {
+		leaf = leaf.child
+	}
+	if block, ok := leaf.value.(*insertValue); ok {
+		count = len(block.values)
+	}
+	parent := target[:len(target)-1]
+	if _, found := valueAt(before, parent); !found {
+		return addedOrReplaced(before, after, parent)
+	}
+	index, err := strconv.Atoi(target[len(target)-1])
+	if err != nil {
+		value, _ := valueAt(after, target)
+		return Patch{{Op: PatchAdd, Path: pointer(target), Value: value}}
+	}
+	patch := make(Patch, count)
+	for i := range patch {
+		path := append(append([]string{}, parent...), strconv.Itoa(index+i))
+		value, _ := valueAt(after, path)
+		patch[i] = PatchOperation{Op: PatchAdd, Path: pointer(path), Value: value}
+	}
+	return patch
+}
+
+// newItemDepth returns the depth of the first node that appends or prepends an item, or -1.
+func (m *Mutator) newItemDepth() int {
+	if m.isNewItem() {
+		return 0
+	}
+	if m.child == nil {
+		return -1
+	}
+	if depth := m.child.newItemDepth(); depth >= 0 {
+		return depth + 1
+	}
+	return -1
+}
+
+func addedOrReplaced(before, after any, target []string) Patch {
+	for i := range target {
+		if _, found := valueAt(before, target[:i+1]); found {
+			continue
+		}
+		parent, _ := valueAt(before, target[:i])
+		index, err := strconv.Atoi(target[i])
+		items, isArray := parent.([]any)
+		if err != nil || !isArray {
+			value, _ := valueAt(after, target[:i+1])
+			return Patch{{Op: PatchAdd, Path: pointer(target[:i+1]), Value: value}}
+		}
+		var patch Patch
+		for j := len(items); j <= index; j++ {
+			path := append(append([]string{}, target[:i]...), strconv.Itoa(j))
+			value, _ := valueAt(after, path)
+			patch = append(patch, PatchOperation{Op: PatchAdd, Path: pointer(path), Value: value})
+		}
+		return patch
+	}
+	value, found := valueAt(after, target)
+	if !found {
+		return nil
+	}
+	return Patch{{Op: PatchReplace, Path: pointer(target), Value: value}}
+}
+
+// targets returns the concrete paths of the content that the mutator refers to.
+func (m *Mutator) targets(content any) [][]string {
+	if m.isSelf() {
+		return [][]string{{}}
+	}
+	if m.recursive {
+		return m.descendantTargets(content)
+	}
+	var tokens []string
+	var children []any
+	items, isArray := content.([]any)
+	if m.IsArray() && (m.name == "" || isArray) {
+		switch {
+		case m.isRange():
+			indexes, _ := m.rangeIndexes(items)
+			for _, i := range indexes {
+				tokens = append(tokens, strconv.Itoa(i))
+				children = append(children, items[i])
+			}
+		case m.index == appendIndex, m.index == pointerAppendIndex:
+			tokens = append(tokens, strconv.Itoa(len(items)))
+			children = append(children, nil)
+		case m.index == prependIndex:
+			tokens = append(tokens, "0")
+			children = append(children, nil)
+		default:
+			index, err := m.resolveIndex(len(items))
+			if err != nil {
+				return nil
+			}
+			var child any
+			if index < len(items) {
+				child = items[index]
+			}
+			tokens = append(tokens, strconv.Itoa(index))
+			children = append(children, child)
+		}
+	} else if attributes, _ := content.(map[string]any); m.isWildcard() {
+		for _, key := range sortedKeys(attributes) {
+			tokens = append(tokens, key)
+			children = append(children, attributes[key])
+		}
+	} else {
+		tokens = append(tokens, m.name)
+		children = append(children, attributes[m.name])
+	}
+	var out [][]string
+	for i, token := range tokens {
+		if m.child == nil {
+			out = append(out, []string{token})
+			continue
+		}
+		for _, sub := range m.child.targets(children[i]) {
+			out = append(out, append([]string{token}, sub...))
+		}
+	}
+	return out
+}
+
+func valueAt(content any, tokens []string) (any, bool) {
+	for _, token := range tokens {
+		switch c := content.(type) {
+		case map[string]any:
+			value, found := c[token]
+			if !found {
+				return nil, false
+			}
+			content = value
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(c) {
+				return nil, false
+			}
+			content = c[index]
+		default:
+			return nil, false
+		}
+	}
+	return content, true
+}
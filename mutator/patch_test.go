@@ -0,0 +1,228 @@
+package mutator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivancorrales/knoa/sanitizer"
+)
+
+func Test_patch_apply(t *testing.T) {
+	tests := []struct {
+		name    string
+		content any
+		patch   string
+		want    any
+		wantErr bool
+	}{
+		{
+			name:    "Add an object member",
+			content: map[string]any{"foo": "bar"},
+			patch:   `[{"op":"add","path":"/baz","value":"qux"}]`,
+			want:    map[string]any{"foo": "bar", "baz": "qux"},
+		},
+		{
+			name:    "Add an array element",
+			content: map[string]any{"foo": []any{"bar", "baz"}},
+			patch:   `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			want:    map[string]any{"foo": []any{"bar", "qux", "baz"}},
+		},
+		{
+			name:    "Append an array element",
+			content: map[string]any{"foo": []any{"bar"}},
+			patch:   `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			want:    map[string]any{"foo": []any{"bar", []any{"abc", "def"}}},
+		},
+		{
+			name:    "Remove an array element",
+			content: map[string]any{"foo": []any{"bar", "qux", "baz"}},
+			patch:   `[{"op":"remove","path":"/foo/1"}]`,
+			want:    map[string]any{"foo": []any{"bar", "baz"}},
+		},
+		{
+			name:    "Replace a value with null",
+			content: map[string]any{"baz": "qux", "foo": "bar"},
+			patch:   `[{"op":"replace","path":"/baz","value":null}]`,
+			want:    map[string]any{"baz": nil, "foo": "bar"},
+		},
+		{
+			name: "Move a value",
+			content: map[string]any{
+				"foo": map[string]any{"bar": "baz", "waldo": "fred"},
+				"qux": map[string]any{"corge": "grault"},
+			},
+			patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			want: map[string]any{
+				"foo": map[string]any{"bar": "baz"},
+				"qux": map[string]any{"corge": "grault", "thud": "fred"},
+			},
+		},
+		{
+			name:    "Move an array element",
+			content: map[string]any{"foo": []any{"all", "grass", "cows", "eat"}},
+			patch:   `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			want:    map[string]any{"foo": []any{"all", "cows", "eat", "grass"}},
+		},
+		{
+			name:    "Copy a value into a numeric key of an object",
+			content: map[string]any{"foo": map[string]any{"1": "a"}, "bar": "b"},
+			patch:   `[{"op":"copy","from":"/bar","path":"/foo/2"}]`,
+			want:    map[string]any{"foo": map[string]any{"1": "a", "2": "b"}, "bar": "b"},
+		},
+		{
+			name:    "Escaped pointers",
+			content: map[string]any{"a/b": 1, "m~n": 2},
+			patch:   `[{"op":"test","path":"/a~1b","value":1},{"op":"replace","path":"/m~0n","value":3}]`,
+			want:    map[string]any{"a/b": 1, "m~n": float64(3)},
+		},
+		{
+			name:    "Failed test leaves the document untouched",
+			content: map[string]any{"baz": "qux", "foo": []any{"a", 2, "c"}},
+			patch:   `[{"op":"remove","path":"/baz"},{"op":"test","path":"/foo/1","value":"2"}]`,
+			want:    map[string]any{"baz": "qux", "foo": []any{"a", 2, "c"}},
+			wantErr: true,
+		},
+		{
+			name:    "Replace a missing attribute",
+			content: map[string]any{"foo": "bar"},
+			patch:   `[{"op":"add","path":"/baz","value":1},{"op":"replace","path":"/missing","value":2}]`,
+			want:    map[string]any{"foo": "bar"},
+			wantErr: true,
+		},
+		{
+			name:    "Remove a missing attribute",
+			content: map[string]any{"foo": "bar"},
+			patch:   `[{"op":"remove","path":"/foo"},{"op":"remove","path":"/missing"}]`,
+			want:    map[string]any{"foo": "bar"},
+			wantErr: true,
+		},
+		{
+			name:    "Remove a missing array element",
+			content: map[string]any{"foo": []any{"bar"}},
+			patch:   `[{"op":"remove","path":"/foo/1"}]`,
+			want:    map[string]any{"foo": []any{"bar"}},
+			wantErr: true,
+		},
+		{
+			name:    "Add to a missing parent",
+			content: map[string]any{"foo": "bar"},
+			patch:   `[{"op":"add","path":"/baz/qux","value":1}]`,
+			want:    map[string]any{"foo": "bar"},
+			wantErr: true,
+		},
+		{
+			name:    "Move a value into one of its children",
+			content: map[string]any{"foo": map[string]any{"bar": 1}},
+			patch:   `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`,
+			want:    map[string]any{"foo": map[string]any{"bar": 1}},
+			wantErr: true,
+		},
+		{
+			name:    "Move a value into a sibling with the same prefix",
+			content: map[string]any{"foo": 1},
+			patch:   `[{"op":"move","from":"/foo","path":"/foobar"}]`,
+			want:    map[string]any{"foobar": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := ParsePatch([]byte(tt.patch))
+			assert.NoError(t, err)
+			mutators, err := NewOperation().Patch(patch)
+			assert.NoError(t, err)
+			got, err := Mutate(tt.content, mutators[0])
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parsePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		{name: "Missing value", patch: `[{"op":"add","path":"/a"}]`},
+		{name: "Missing from", patch: `[{"op":"copy","path":"/a"}]`},
+		{name: "Missing path", patch: `[{"op":"remove"}]`},
+		{name: "Unknown operation", patch: `[{"op":"rename","path":"/a"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePatch([]byte(tt.patch))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_newPatch(t *testing.T) {
+	pathRegExp, attrRegExp := RegExpsFromAttributeFormat(DefAttributeNameFormat)
+	p := &Parser{RegExp: pathRegExp, AttributeRegExp: attrRegExp}
+	op := NewOperation()
+	var mutators []Mutator
+	set, _ := op.Set(p, sanitizer.PathValueList{
+		{Path: "name", Value: "api"},
+		{Path: "replicas", Value: 3},
+		{Path: "ports[3]", Value: 8080},
+		{Path: "containers[*].image", Value: "nginx"},
+		{Path: "labels.tier", Value: "backend"},
+	})
+	mutators = append(mutators, set...)
+	unset, _ := op.Unset(p, []sanitizer.Path{"owner", "ports[0]"})
+	mutators = append(mutators, unset...)
+	content := map[string]any{
+		"replicas":   1,
+		"owner":      "platform",
+		"ports":      []any{80, 443},
+		"containers": []any{map[string]any{"image": "httpd"}, map[string]any{"image": "envoy"}},
+	}
+	patch, err := NewPatch(content, mutators)
+	assert.NoError(t, err)
+	got, _ := json.Marshal(patch)
+	assert.JSONEq(t, `[
+		{"op":"add","path":"/name","value":"api"},
+		{"op":"replace","path":"/replicas","value":3},
+		{"op":"add","path":"/ports/2","value":null},
+		{"op":"add","path":"/ports/3","value":8080},
+		{"op":"replace","path":"/containers/0/image","value":"nginx"},
+		{"op":"replace","path":"/containers/1/image","value":"nginx"},
+		{"op":"add","path":"/labels","value":{"tier":"backend"}},
+		{"op":"remove","path":"/owner"},
+		{"op":"remove","path":"/ports/0"}
+	]`, string(got))
+}
+
+func Test_newPatch_missingArrays(t *testing.T) {
+	pathRegExp, attrRegExp := RegExpsFromAttributeFormat(DefAttributeNameFormat)
+	p := &Parser{RegExp: pathRegExp, AttributeRegExp: attrRegExp}
+	op := NewOperation()
+	var mutators []Mutator
+	set, _ := op.Set(p, sanitizer.PathValueList{
+		{Path: "spec.tags[+]", Value: "a"},
+		{Path: "spec.tags[^]", Value: "b"},
+	})
+	mutators = append(mutators, set...)
+	insert, _ := op.Insert(p, "spec.ports", 0, []any{80, 443})
+	mutators = append(mutators, insert...)
+	content := map[string]any{"spec": map[string]any{}}
+	patch, err := NewPatch(content, mutators)
+	assert.NoError(t, err)
+	got, _ := json.Marshal(patch)
+	assert.JSONEq(t, `[
+		{"op":"add","path":"/spec/tags","value":["a"]},
+		{"op":"add","path":"/spec/tags/0","value":"b"},
+		{"op":"add","path":"/spec/ports","value":[80,443]}
+	]`, string(got))
+
+	patchMutators, err := op.Patch(patch)
+	assert.NoError(t, err)
+	out, err := Mutate(content, patchMutators[0])
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"spec": map[string]any{"tags": []any{"b", "a"}, "ports": []any{80, 443}}}, out)
+}
@@ -0,0 +1,248 @@
+package mutator
+
+import (
+	"fmt"
+
+	"github.com/ivancorrales/knoa/internal"
+	"github.com/ivancorrales/knoa/sanitizer"
+)
+
+const mergePatchOperation = "mergePatch"
+
+// Operation is the serializable form of a mutator, that can be replayed over any other document.
+// Paths are written in the dotted syntax.
+type Operation struct {
+	Op    string        `json:"op"`
+	Path  string        `json:"path,omitempty"`
+	From  string        `json:"from,omitempty"`
+	Value any           `json:"value,omitempty"`
+	Merge *MergeOptions `json:"merge,omitempty"`
+	Patch Patch         `json:"patch,omitempty"`
+}
+
+type MergeOptions struct {
+	ArrayStrategy  string `json:"arrayStrategy,omitempty"`
+	KeyField       string `json:"keyField,omitempty"`
+	ConflictPolicy string `json:"conflictPolicy,omitempty"`
+}
+
+var arrayStrategyNames = map[ArrayStrategy]string{
+	ReplaceArrays:      "replace",
+	AppendArrays:       "append",
+	MergeArraysByIndex: "index",
+	MergeArraysByKey:   "key",
+}
+
+var conflictPolicyNames = map[ConflictPolicy]string{
+	OverrideOnConflict: "override",
+	KeepOnConflict:     "keep",
+	FailOnConflict:     "fail",
+}
+
+// Export returns the operation the mutator performs. The funcs of apply operations can't be
+// serialized, so only their paths are exported.
+func (m *Mutator) Export() Operation {
+	if m.child == nil {
+		return Operation{Op: m.Operation()}
+	}
+	switch v := m.child.value.(type) {
+	case *mergeValue:
+		if v.merger.mergePatch {
+			return Operation{Op: mergePatchOperation, Value: internal.Normalize(v.content)}
+		}
+		return Operation{
+			Op:    m.Operation(),
+			Value: internal.Normalize(v.content),
+			Merge: &MergeOptions{
+				ArrayStrategy:  arrayStrategyNames[v.merger.arrayStrategy],
+				KeyField:       v.merger.keyField,
+				ConflictPolicy: conflictPolicyNames[v.merger.conflictPolicy],
+			},
+		}
+	case *transferValue:
+		return Operation{Op: m.Operation(), From: v.from.Path(), Path: v.to.Path()}
+	case *patchValue:
+		return Operation{Op: m.Operation(), Patch: v.patch}
+	}
+	o := Operation{Op: m.Operation(), Path: m.Path()}
+	switch m.operation {
+	case setOp, insertOp, restoreOp:
+		leaf := m
+		for leaf.child != nil {
+			leaf = leaf.child
+		}
+		// the values inserted as a block are exported as a list
+		if block, ok := leaf.value.(*insertValue); ok {
+			o.Value = internal.Normalize(block.values)
+		} else {
+			o.Value = internal.Normalize(leaf.value)
+		}
+	}
+	return o
+}
+
+// Export returns the operations the mutators perform over the content. JSON Pointer tokens, that
+// could be either an index or a key, are exported as they were applied to the content.
+func Export(content any, mutators []Mutator) []Operation {
+	ops := make([]Operation, len(mutators))
+	resolve := false
+	for i := range mutators {
+		resolve = resolve || mutators[i].hasPointerTokens()
+	}
+	doc := internal.Normalize(content)
+	for i := range mutators {
+		if !resolve {
+			ops[i] = mutators[i].Export()
+			continue
+		}
+		ops[i] = mutators[i].resolveTokens(doc).Export()
+		if out, err := Mutate(doc, mutators[i]); err == nil {
+			doc = out
+		}
+	}
+	return ops
+}
+
+func (m *Mutator) hasPointerTokens() bool {
+	if v, ok := m.value.(*transferValue); ok && (v.from.hasPointerTokens() || v.to.hasPointerTokens()) {
+		return true
+	}
+	return m.isPointerToken() || (m.child != nil && m.child.hasPointerTokens())
+}
+
+// isPointerToken reports whether the node is a JSON Pointer token that could be either an index or a key.
+func (m *Mutator) isPointerToken() bool {
+	return m.literal && m.index != "" && m.name == m.index
+}
+
+// resolveTokens returns a copy of the mutator where the JSON Pointer tokens are either an index or a
+// key, depending on the content they are applied to.
+func (m *Mutator) resolveTokens(content any) *Mutator {
+	r := m.clone()
+	if r.child == nil {
+		return r
+	}
+	if v, ok := r.child.value.(*transferValue); ok {
+		resolved := *v
+		resolved.from, resolved.to = v.from.resolveTokens(content), v.to.resolveTokens(content)
+		r.child.value = &resolved
+		return r
+	}
+	node := content
+	for n := r.child; n != nil; n = n.child {
+		switch c := node.(type) {
+		case map[string]any:
+			if n.isPointerToken() {
+				n.index = ""
+			}
+			node = c[n.name]
+		case []any:
+			if n.isPointerToken() {
+				n.name = ""
+			}
+			node = nil
+			if index, err := n.resolveIndex(len(c)); err == nil && index < len(c) {
+				node = c[index]
+			}
+		default:
+			if n.isPointerToken() {
+				n.name = ""
+			}
+			node = nil
+		}
+	}
+	return r
+}
+
+// Replay turns the exported operations back into mutators. It fails if any of them can't be replayed.
+func (op *operation) Replay(ops []Operation) ([]Mutator, error) {
+	parser := &Parser{}
+	var mutators []Mutator
+	for _, o := range ops {
+		out, err := op.replay(parser, o)
+		if err != nil {
+			return nil, fmt.Errorf("operation '%s' can't be replayed: %w", o.Op, err)
+		}
+		mutators = append(mutators, out...)
+	}
+	return mutators, nil
+}
+
+func (op *operation) replay(parser *Parser, o Operation) ([]Mutator, error) {
+	switch o.Op {
+	case operationNames[setOp]:
+		return op.Set(parser, sanitizer.PathValueList{{Path: o.Path, Value: o.Value}})
+	case operationNames[unsetOp]:
+		return op.Unset(parser, []sanitizer.Path{o.Path})
+	case operationNames[insertOp]:
+		m, err := parser.Parse(op.path(o.Path))
+		if err != nil {
+			return nil, err
+		}
+		values, ok := o.Value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid values '%v', expected a list", o.Value)
+		}
+		m.operation = insertOp
+		m.addValueToNode(&insertValue{values: values})
+		return []Mutator{*m}, nil
+	case operationNames[restoreOp]:
+		return op.Restore(o.Value), nil
+	case operationNames[moveOp]:
+		return op.Move(parser, o.From, o.Path)
+	case operationNames[copyOp]:
+		return op.Copy(parser, o.From, o.Path)
+	case operationNames[mergeOp]:
+		opts, err := o.Merge.options()
+		if err != nil {
+			return nil, err
+		}
+		return op.Merge(o.Value, opts...), nil
+	case mergePatchOperation:
+		patch, ok := o.Value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid merge patch '%v'", o.Value)
+		}
+		return op.MergePatch(patch), nil
+	case operationNames[patchOp]:
+		return op.Patch(o.Patch)
+	case operationNames[applyOp], operationNames[applyCtxOp]:
+		return nil, fmt.Errorf("the func applied on '%s' is not serializable", o.Path)
+	}
+	return nil, fmt.Errorf("unknown operation")
+}
+
+func (o *MergeOptions) options() ([]MergeOpt, error) {
+	if o == nil {
+		return nil, nil
+	}
+	var opts []MergeOpt
+	if o.ArrayStrategy != "" {
+		strategy, found := lookupName(arrayStrategyNames, o.ArrayStrategy)
+		if !found {
+			return nil, fmt.Errorf("unknown array strategy '%s'", o.ArrayStrategy)
+		}
+		opts = append(opts, WithArrayStrategy(strategy))
+	}
+	if o.KeyField != "" {
+		opts = append(opts, WithArrayKeyField(o.KeyField))
+	}
+	if o.ConflictPolicy != "" {
+		policy, found := lookupName(conflictPolicyNames, o.ConflictPolicy)
+		if !found {
+			return nil, fmt.Errorf("unknown conflict policy '%s'", o.ConflictPolicy)
+		}
+		opts = append(opts, WithConflictPolicy(policy))
+	}
+	return opts, nil
+}
+
+func lookupName[K comparable](names map[K]string, name string) (K, bool) {
+	for k, n := range names {
+		if n == name {
+			return k, true
+		}
+	}
+	var zero K
+	return zero, false
+}
@@ -0,0 +1,103 @@
+package mutator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivancorrales/knoa/sanitizer"
+)
+
+func Test_operation_replay(t *testing.T) {
+	parser := &Parser{}
+	op := NewOperation()
+	set, _ := op.Set(parser, sanitizer.PathValueList{
+		{Path: "name", Value: "api"},
+		{Path: "labels.'app.io/tier'", Value: "backend"},
+		{Path: "ports[+]", Value: 8080},
+		{Path: "containers[0].env", Value: map[string]any{"DEBUG": true}},
+	})
+	unset, _ := op.Unset(parser, []sanitizer.Path{"owner"})
+	insert, _ := op.Insert(parser, "ports", 0, []any{80})
+	rename, _ := op.Rename(parser, "containers[0].env", "variables")
+	patch, _ := op.Patch(Patch{{Op: PatchReplace, Path: "/name", Value: "web"}})
+	var mutators []Mutator
+	mutators = append(mutators, op.Restore(map[string]any{"owner": "platform", "tier": "backend"})...)
+	mutators = append(mutators, set...)
+	mutators = append(mutators, unset...)
+	mutators = append(mutators, insert...)
+	mutators = append(mutators, rename...)
+	mutators = append(mutators, op.Merge(map[string]any{"ports": []any{443}}, WithArrayStrategy(AppendArrays))...)
+	mutators = append(mutators, op.MergePatch(map[string]any{"replicas": 2.0})...)
+	mutators = append(mutators, patch...)
+
+	ops := make([]Operation, len(mutators))
+	for i := range mutators {
+		ops[i] = mutators[i].Export()
+	}
+	content, err := json.Marshal(ops)
+	assert.NoError(t, err)
+	var decoded []Operation
+	assert.NoError(t, json.Unmarshal(content, &decoded))
+	replayed, err := op.Replay(decoded)
+	assert.NoError(t, err)
+
+	var want, got any = map[string]any{"owner": "platform"}, map[string]any{"owner": "platform"}
+	for i := range mutators {
+		want, err = Mutate(want, mutators[i])
+		assert.NoError(t, err)
+	}
+	for i := range replayed {
+		got, err = Mutate(got, replayed[i])
+		assert.NoError(t, err)
+	}
+	wantJSON, _ := json.Marshal(want)
+	gotJSON, _ := json.Marshal(got)
+	assert.JSONEq(t, string(wantJSON), string(gotJSON))
+	assert.JSONEq(t, `{
+		"name": "web",
+		"tier": "backend",
+		"labels": {"app.io/tier": "backend"},
+		"ports": [80, 8080, 443],
+		"containers": [{"variables": {"DEBUG": true}}],
+		"replicas": 2
+	}`, string(gotJSON))
+}
+
+func Test_operation_replayErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		op   Operation
+		err  string
+	}{
+		{name: "apply", op: Operation{Op: "apply", Path: "a"}, err: "operation 'apply' can't be replayed: the func applied on 'a' is not serializable"},
+		{name: "unknown", op: Operation{Op: "add", Path: "a"}, err: "operation 'add' can't be replayed: unknown operation"},
+		{name: "invalid path", op: Operation{Op: "set", Path: "a["}, err: "operation 'set' can't be replayed: invalid path 'a[': missing ']' at column 2"},
+		{name: "invalid merge", op: Operation{Op: "merge", Merge: &MergeOptions{ArrayStrategy: "zip"}}, err: "operation 'merge' can't be replayed: unknown array strategy 'zip'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutators, err := NewOperation().Replay([]Operation{tt.op})
+			assert.Nil(t, mutators)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func Test_export_pointerTokens(t *testing.T) {
+	parser := &Parser{Syntax: PointerSyntax}
+	set, err := NewOperation().Set(parser, sanitizer.PathValueList{
+		{Path: "/ports/80", Value: "http"},
+		{Path: "/items/0", Value: "a"},
+		{Path: "/items/-", Value: "b"},
+	})
+	assert.NoError(t, err)
+	content := map[string]any{"ports": map[string]any{}, "items": []any{}}
+	var paths []string
+	for _, o := range Export(content, set) {
+		paths = append(paths, o.Path)
+	}
+	assert.Equal(t, []string{`ports."80"`, "items[0]", "items[-]"}, paths)
+	assert.Equal(t, "ports[80]", set[0].Export().Path, "without the content, the tokens are taken as indexes")
+}
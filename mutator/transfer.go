@@ -0,0 +1,224 @@
+package mutator
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ivancorrales/knoa/internal"
+)
+
+type transferValue struct {
+	from     *Mutator
+	to       *Mutator
+	fromPath string
+	toPath   string
+	targetOp operationCode
+	// op is set when the transfer comes from a JSON Patch document.
+	op PatchOperation
+}
+
+// transfer copies, or moves, the values the source path points to. Wildcards in the
+// target path take the indexes matched by the wildcards of the source path.
+func (m *Mutator) transfer(content any) (any, error) {
+	v, ok := m.value.(*transferValue)
+	if !ok {
+		return content, fmt.Errorf("invalid transfer value")
+	}
+	bindings := v.from.child.bindings(content, nil)
+	if len(bindings) == 0 {
+		return content, notFound(v.fromPath)
+	}
+	values := make([]any, len(bindings))
+	targets := make([]*Mutator, len(bindings))
+	bound := make(map[string]bool, len(bindings))
+	for i, indexes := range bindings {
+		from, err := v.from.bind(indexes)
+		if err != nil {
+			return content, err
+		}
+		values[i] = internal.Normalize(from.child.Lookup(content)[0])
+		if targets[i], err = v.to.bind(indexes); err != nil {
+			return content, &PathError{Path: v.toPath, Reason: fmt.Sprintf("can't be resolved from '%s': %v", v.fromPath, err)}
+		}
+		// the values would overwrite each other, unless they are added as new items
+		path := targets[i].Path()
+		if bound[path] && !targets[i].addsItems() {
+			return content, &PathError{Path: v.toPath, Reason: fmt.Sprintf("takes the same location '%s' for more than one value of '%s'", path, v.fromPath)}
+		}
+		bound[path] = true
+		if m.operation == moveOp && within(targets[i].child.targets(content), from.child.targets(content)) {
+			return content, &PathError{Path: v.toPath, Reason: fmt.Sprintf("is inside '%s', that is moved", v.fromPath)}
+		}
+	}
+	out := content
+	if m.operation == moveOp {
+		for i := len(bindings) - 1; i >= 0; i-- {
+			from, _ := v.from.bind(bindings[i])
+			from.operation = unsetOp
+			var err error
+			if out, err = Mutate(out, *from); err != nil {
+				return content, err
+			}
+		}
+	}
+	for i, to := range targets {
+		to.operation = v.targetOp
+		to.addValueToNode(values[i])
+		var err error
+		if out, err = Mutate(out, *to); err != nil {
+			return content, err
+		}
+	}
+	return out, nil
+}
+
+func (v *transferValue) patchOperations(operation operationCode, before any) Patch {
+	if v.op.Op != "" {
+		return Patch{v.op}
+	}
+	bindings := v.from.child.bindings(before, nil)
+	values := make([]any, len(bindings))
+	for i, indexes := range bindings {
+		from, _ := v.from.bind(indexes)
+		values[i] = internal.Normalize(from.child.Lookup(before)[0])
+	}
+	var patch Patch
+	doc := internal.Normalize(before)
+	if operation == moveOp {
+		for i := len(bindings) - 1; i >= 0; i-- {
+			from, _ := v.from.bind(bindings[i])
+			patch = append(patch, PatchOperation{Op: PatchRemove, Path: pointer(from.child.targets(doc)[0])})
+			from.operation = unsetOp
+			doc, _ = Mutate(doc, *from)
+		}
+	}
+	for i, indexes := range bindings {
+		to, err := v.to.bind(indexes)
+		if err != nil {
+			continue
+		}
+		to.operation = v.targetOp
+		to.addValueToNode(values[i])
+		next, err := Mutate(internal.Normalize(doc), *to)
+		if err != nil {
+			continue
+		}
+		patch = append(patch, addedOrReplaced(doc, next, to.child.targets(doc)[0])...)
+		doc = next
+	}
+	return patch
+}
+
+// addsItems reports whether any of the nodes appends or prepends an item to an array.
+func (m *Mutator) addsItems() bool {
+	for n := m; n != nil; n = n.child {
+		if n.isNewItem() {
+			return true
+		}
+	}
+	return false
+}
+
+// within reports whether any of the paths is a descendant of any of the parents.
+func within(paths, parents [][]string) bool {
+	for _, path := range paths {
+		for _, parent := range parents {
+			if isDescendant(path, parent) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isDescendant(path, parent []string) bool {
+	if len(path) <= len(parent) {
+		return false
+	}
+	for i := range parent {
+		if path[i] != parent[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// bindings returns the indexes taken by the wildcards for every node of the content the mutator matches.
+func (m *Mutator) bindings(content any, indexes []string) [][]string {
+	if m.isSelf() {
+		return [][]string{indexes}
+	}
+	var children []any
+	var childIndexes [][]string
+	switch items := content.(type) {
+	case []any:
+		if !m.IsArray() {
+			return nil
+		}
+		if m.isRange() {
+			matched, err := m.rangeIndexes(items)
+			if err != nil {
+				return nil
+			}
+			for _, i := range matched {
+				children = append(children, items[i])
+				childIndexes = append(childIndexes, append(append([]string{}, indexes...), strconv.Itoa(i)))
+			}
+		} else {
+			index, err := m.resolveIndex(len(items))
+			if err != nil || index >= len(items) {
+				return nil
+			}
+			children = append(children, items[index])
+			childIndexes = append(childIndexes, indexes)
+		}
+	case map[string]any:
+		if m.isWildcard() {
+			for _, key := range sortedKeys(items) {
+				children = append(children, items[key])
+				childIndexes = append(childIndexes, append(append([]string{}, indexes...), key))
+			}
+			break
+		}
+		value, found := items[m.name]
+		if (m.name == "" && !m.literal) || !found {
+			return nil
+		}
+		children = append(children, value)
+		childIndexes = append(childIndexes, indexes)
+	default:
+		return nil
+	}
+	var out [][]string
+	for i := range children {
+		if m.child == nil {
+			out = append(out, childIndexes[i])
+			continue
+		}
+		out = append(out, m.child.bindings(children[i], childIndexes[i])...)
+	}
+	return out
+}
+
+// bind returns a copy of the mutator whose wildcards are replaced by the given indexes.
+func (m *Mutator) bind(indexes []string) (*Mutator, error) {
+	c := *m
+	if c.isRange() || c.isWildcard() {
+		if len(indexes) == 0 {
+			return nil, fmt.Errorf("there are more wildcards than matched indexes")
+		}
+		if c.isWildcard() {
+			c.name, c.literal, indexes = indexes[0], true, indexes[1:]
+		} else {
+			c.index, indexes = indexes[0], indexes[1:]
+		}
+	}
+	if m.child != nil {
+		child, err := m.child.bind(indexes)
+		if err != nil {
+			return nil, err
+		}
+		c.child = child
+	}
+	return &c, nil
+}
@@ -0,0 +1,160 @@
+package mutator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_operation_transfer(t *testing.T) {
+	pathRegExp, attrRegExp := RegExpsFromAttributeFormat(DefAttributeNameFormat)
+	p := &Parser{RegExp: pathRegExp, AttributeRegExp: attrRegExp}
+	tests := []struct {
+		name     string
+		mutators func() ([]Mutator, error)
+		content  any
+		want     any
+		wantErr  bool
+	}{
+		{
+			name: "Move an attribute",
+			mutators: func() ([]Mutator, error) {
+				return NewOperation().Move(p, "spec.image", "image")
+			},
+			content: map[string]any{"spec": map[string]any{"image": "api", "replicas": 1}},
+			want:    map[string]any{"image": "api", "spec": map[string]any{"replicas": 1}},
+		},
+		{
+			name: "Copy an item of an array into an attribute",
+			mutators: func() ([]Mutator, error) {
+				return NewOperation().Copy(p, "ports[1]", "spec.port")
+			},
+			content: map[string]any{"ports": []any{80, 443}},
+			want:    map[string]any{"ports": []any{80, 443}, "spec": map[string]any{"port": 443}},
+		},
+		{
+			name: "Move the items of an array into another array",
+			mutators: func() ([]Mutator, error) {
+				return NewOperation().Move(p, "items[*].tags", "tags[*]")
+			},
+			content: map[string]any{"items": []any{
+				map[string]any{"name": "a", "tags": []any{"x"}},
+				map[string]any{"name": "b", "tags": []any{"y"}},
+			}},
+			want: map[string]any{
+				"items": []any{map[string]any{"name": "a"}, map[string]any{"name": "b"}},
+				"tags":  []any{[]any{"x"}, []any{"y"}},
+			},
+		},
+		{
+			name: "Rename an attribute that isn't in the document",
+			mutators: func() ([]Mutator, error) {
+				return NewOperation().Rename(p, "items[*].oldName", "newName")
+			},
+			content: []any{},
+			want:    []any{},
+			wantErr: true,
+		},
+		{
+			name: "Rename an attribute with wildcards",
+			mutators: func() ([]Mutator, error) {
+				return NewOperation().Rename(p, "items[*].oldName", "newName")
+			},
+			content: map[string]any{"items": []any{
+				map[string]any{"oldName": "a"},
+				map[string]any{"oldName": "b", "newName": "c"},
+			}},
+			want: map[string]any{"items": []any{
+				map[string]any{"newName": "a"},
+				map[string]any{"newName": "b"},
+			}},
+		},
+		{
+			name: "Wildcards in the target path must be matched in the source path",
+			mutators: func() ([]Mutator, error) {
+				return NewOperation().Copy(p, "name", "items[*].name")
+			},
+			content: map[string]any{"name": "a", "items": []any{map[string]any{}}},
+			want:    map[string]any{"name": "a", "items": []any{map[string]any{}}},
+			wantErr: true,
+		},
+		{
+			name: "Move a path that doesn't exist",
+			mutators: func() ([]Mutator, error) {
+				return NewOperation().Move(p, "spec.image", "image")
+			},
+			content: map[string]any{"spec": map[string]any{}},
+			want:    map[string]any{"spec": map[string]any{}},
+			wantErr: true,
+		},
+		{
+			name: "Move several values into the same attribute",
+			mutators: func() ([]Mutator, error) {
+				return NewOperation().Move(p, "items[*]", "item")
+			},
+			content: map[string]any{"items": []any{1, 2, 3}},
+			want:    map[string]any{"items": []any{1, 2, 3}},
+			wantErr: true,
+		},
+		{
+			name: "Move several values into the same items of an array",
+			mutators: func() ([]Mutator, error) {
+				return NewOperation().Move(p, "items[*].tags[*]", "tags[*]")
+			},
+			content: map[string]any{"items": []any{
+				map[string]any{"tags": []any{"x", "y"}},
+				map[string]any{"tags": []any{"z"}},
+			}},
+			want: map[string]any{"items": []any{
+				map[string]any{"tags": []any{"x", "y"}},
+				map[string]any{"tags": []any{"z"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "Move several values appending them to an array",
+			mutators: func() ([]Mutator, error) {
+				return NewOperation().Move(p, "items[*]", "all[+]")
+			},
+			content: map[string]any{"items": []any{1, 2, 3}},
+			want:    map[string]any{"items": []any{}, "all": []any{1, 2, 3}},
+		},
+		{
+			name: "Move an attribute inside itself",
+			mutators: func() ([]Mutator, error) {
+				return NewOperation().Move(p, "spec", "spec.previous")
+			},
+			content: map[string]any{"spec": map[string]any{"replicas": 1}},
+			want:    map[string]any{"spec": map[string]any{"replicas": 1}},
+			wantErr: true,
+		},
+		{
+			name: "Copy an attribute inside itself",
+			mutators: func() ([]Mutator, error) {
+				return NewOperation().Copy(p, "spec", "spec.previous")
+			},
+			content: map[string]any{"spec": map[string]any{"replicas": 1}},
+			want:    map[string]any{"spec": map[string]any{"replicas": 1, "previous": map[string]any{"replicas": 1}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutators, err := tt.mutators()
+			assert.NoError(t, err)
+			got, err := Mutate(tt.content, mutators[0])
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_operation_renameArrayItem(t *testing.T) {
+	pathRegExp, attrRegExp := RegExpsFromAttributeFormat(DefAttributeNameFormat)
+	p := &Parser{RegExp: pathRegExp, AttributeRegExp: attrRegExp}
+	_, err := NewOperation().Rename(p, "items[0]", "first")
+	assert.Error(t, err)
+}
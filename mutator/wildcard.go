@@ -0,0 +1,141 @@
+package mutator
+
+import (
+	"sort"
+	"strconv"
+)
+
+const recursiveSeparator = ".."
+
+// isWildcard reports whether the node refers to every attribute of a map.
+func (m *Mutator) isWildcard() bool {
+	return !m.literal && m.index == "" && m.name == wildcardIndex
+}
+
+// fansOut reports whether the node only matches attributes that already exist.
+func (m *Mutator) fansOut() bool {
+	return m.recursive || m.isWildcard()
+}
+
+func (m *Mutator) hasDescent() bool {
+	return m.recursive || (m.child != nil && m.child.hasDescent())
+}
+
+func isContainer(content any) bool {
+	switch content.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
+}
+
+func sortedKeys(content map[string]any) []string {
+	keys := make([]string, 0, len(content))
+	for k := range content {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toAttributes applies the mutator to every attribute of the map.
+func (m *Mutator) toAttributes(content map[string]any) (map[string]any, error) {
+	if m.child == nil && m.operation == unsetOp {
+		for k := range content {
+			delete(content, k)
+		}
+		return content, nil
+	}
+	for _, key := range sortedKeys(content) {
+		if m.child != nil && !isContainer(content[key]) {
+			continue
+		}
+		attr := *m
+		attr.name, attr.literal = key, true
+		attr.loc.indexes = append(append([]string{}, m.loc.indexes...), key)
+		var err error
+		if content, err = attr.ToMap(content); err != nil {
+			return nil, err
+		}
+	}
+	return content, nil
+}
+
+// descend applies the mutator to the attributes with its name at any depth of the content.
+func (m *Mutator) descend(content any) (any, error) {
+	switch c := content.(type) {
+	case map[string]any:
+		for _, key := range sortedKeys(c) {
+			sub := *m
+			sub.loc = m.loc.attribute(key)
+			value, err := sub.descend(c[key])
+			if err != nil {
+				return nil, err
+			}
+			c[key] = value
+		}
+		attr := *m
+		attr.recursive = false
+		if attr.isWildcard() {
+			return attr.toAttributes(c)
+		}
+		if value, found := c[m.name]; !found || (m.child != nil && !isContainer(value)) {
+			return c, nil
+		}
+		return attr.ToMap(c)
+	case []any:
+		for i := range c {
+			sub := *m
+			sub.loc = m.loc.item(i, false)
+			value, err := sub.descend(c[i])
+			if err != nil {
+				return nil, err
+			}
+			c[i] = value
+		}
+		return c, nil
+	}
+	return content, nil
+}
+
+func (m *Mutator) lookupDescendants(content any) []any {
+	var values []any
+	switch c := content.(type) {
+	case map[string]any:
+		attr := *m
+		attr.recursive = false
+		values = append(values, attr.Lookup(c)...)
+		for _, key := range sortedKeys(c) {
+			values = append(values, m.lookupDescendants(c[key])...)
+		}
+	case []any:
+		for _, item := range c {
+			values = append(values, m.lookupDescendants(item)...)
+		}
+	}
+	return values
+}
+
+func (m *Mutator) descendantTargets(content any) [][]string {
+	var out [][]string
+	switch c := content.(type) {
+	case map[string]any:
+		if _, found := c[m.name]; found || m.isWildcard() {
+			attr := *m
+			attr.recursive = false
+			out = append(out, attr.targets(c)...)
+		}
+		for _, key := range sortedKeys(c) {
+			for _, sub := range m.descendantTargets(c[key]) {
+				out = append(out, append([]string{key}, sub...))
+			}
+		}
+	case []any:
+		for i, item := range c {
+			for _, sub := range m.descendantTargets(item) {
+				out = append(out, append([]string{strconv.Itoa(i)}, sub...))
+			}
+		}
+	}
+	return out
+}
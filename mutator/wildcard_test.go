@@ -0,0 +1,146 @@
+package mutator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_mutator_wildcards(t *testing.T) {
+	content := func() map[string]any {
+		return map[string]any{
+			"services": map[string]any{
+				"api": map[string]any{"replicas": 1, "password": "a"},
+				"db":  map[string]any{"replicas": 3, "config": map[string]any{"password": "b"}},
+				"url": "http://localhost",
+			},
+			"users":    []any{map[string]any{"name": "John", "password": "c"}},
+			"password": "d",
+		}
+	}
+	tests := []struct {
+		name     string
+		pathExpr string
+		op       operationCode
+		value    any
+		want     map[string]any
+	}{
+		{
+			name:     "Set an attribute of every map",
+			pathExpr: "services.*.replicas",
+			value:    2,
+			want: map[string]any{
+				"services": map[string]any{
+					"api": map[string]any{"replicas": 2, "password": "a"},
+					"db":  map[string]any{"replicas": 2, "config": map[string]any{"password": "b"}},
+					"url": "http://localhost",
+				},
+				"users":    []any{map[string]any{"name": "John", "password": "c"}},
+				"password": "d",
+			},
+		},
+		{
+			name:     "Unset every attribute of a map",
+			pathExpr: "services.db.*",
+			op:       unsetOp,
+			want: map[string]any{
+				"services": map[string]any{
+					"api": map[string]any{"replicas": 1, "password": "a"},
+					"db":  map[string]any{},
+					"url": "http://localhost",
+				},
+				"users":    []any{map[string]any{"name": "John", "password": "c"}},
+				"password": "d",
+			},
+		},
+		{
+			name:     "Unset an attribute at any depth",
+			pathExpr: "..password",
+			op:       unsetOp,
+			want: map[string]any{
+				"services": map[string]any{
+					"api": map[string]any{"replicas": 1},
+					"db":  map[string]any{"replicas": 3, "config": map[string]any{}},
+					"url": "http://localhost",
+				},
+				"users": []any{map[string]any{"name": "John"}},
+			},
+		},
+		{
+			name:     "Set an attribute at any depth below a path",
+			pathExpr: "services..password",
+			value:    "***",
+			want: map[string]any{
+				"services": map[string]any{
+					"api": map[string]any{"replicas": 1, "password": "***"},
+					"db":  map[string]any{"replicas": 3, "config": map[string]any{"password": "***"}},
+					"url": "http://localhost",
+				},
+				"users":    []any{map[string]any{"name": "John", "password": "c"}},
+				"password": "d",
+			},
+		},
+		{
+			name:     "Wildcards don't create missing paths",
+			pathExpr: "missing.*.replicas",
+			value:    2,
+			want:     content(),
+		},
+		{
+			name:     "Recursive descent doesn't create missing paths",
+			pathExpr: "missing..password",
+			value:    "***",
+			want:     content(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathRegExp, attrRegExp := RegExpsFromAttributeFormat(DefAttributeNameFormat)
+			p := &Parser{RegExp: pathRegExp, AttributeRegExp: attrRegExp}
+			m, err := p.Parse(tt.pathExpr)
+			assert.NoError(t, err)
+			m.operation = tt.op
+			m.addValueToNode(tt.value)
+			got, err := Mutate(content(), *m)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_mutator_lookupWildcards(t *testing.T) {
+	content := map[string]any{
+		"a": map[string]any{"name": "x", "b": map[string]any{"name": "y"}},
+		"c": []any{map[string]any{"name": "z"}},
+	}
+	tests := []struct {
+		name    string
+		mutator *Mutator
+		want    []any
+	}{
+		{
+			name:    "Every attribute of a map",
+			mutator: &Mutator{name: "a", child: &Mutator{name: "*"}},
+			want:    []any{map[string]any{"name": "y"}, "x"},
+		},
+		{
+			name:    "Recursive descent",
+			mutator: &Mutator{name: "name", recursive: true},
+			want:    []any{"x", "y", "z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.mutator.Lookup(content))
+		})
+	}
+}
+
+func Test_mutator_wildcardsOverEmptyKeys(t *testing.T) {
+	m, err := (&Parser{}).Parse("*")
+	assert.NoError(t, err)
+	m.addValueToNode(0)
+	got, err := Mutate(map[string]any{"": 1, "*": 2, "a": 3}, *m)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"": 0, "*": 0, "a": 0}, got)
+}
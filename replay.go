@@ -0,0 +1,41 @@
+package knoa
+
+import (
+	"github.com/ivancorrales/knoa/mutator"
+)
+
+// Operations returns the serializable log of the operations applied to the document.
+func (k *knoa[T]) Operations() []Operation {
+	return mutator.Export(k.content, k.mutators)
+}
+
+// Replay applies the operations, as exported by Operations, over the base document. Either all
+// of them are applied or none is, in which case the error is recorded.
+func Replay[T Type](base Knoa[T], ops []Operation) Knoa[T] {
+	if r, ok := base.(interface{ replay([]Operation) Knoa[T] }); ok {
+		return r.replay(ops)
+	}
+	return unwrap(base).replay(ops)
+}
+
+func (k *knoa[T]) replay(ops []Operation) Knoa[T] {
+	return k.Tx(func(tx Knoa[T]) error {
+		mutators, err := mutator.NewOperation().Replay(ops)
+		if err != nil {
+			return err
+		}
+		tx.(*knoa[T]).push(mutators, nil)
+		return nil
+	})
+}
+
+// unwrap returns the document behind any implementation of Knoa. Those that are not defined
+// in this package are loaded from their output.
+func unwrap[T Type](k Knoa[T]) *knoa[T] {
+	if doc, ok := k.(*knoa[T]); ok {
+		return doc
+	}
+	doc := load[T](k.Out())
+	doc.err = k.Error()
+	return doc
+}
@@ -1,8 +1,11 @@
 package sanitizer
 
 import (
-	"log"
+	"errors"
+	"fmt"
 	"reflect"
+
+	"github.com/ivancorrales/knoa/internal"
 )
 
 var emptyValue = struct{}{}
@@ -11,13 +14,21 @@ var emptyFunc = func(in any) any {
 	return in
 }
 
+// Path is either a path expression or a CompiledPath.
+type Path any
+
+// CompiledPath is implemented by the paths parsed in advance, this is, mutator.Path.
+type CompiledPath interface {
+	Expr() string
+}
+
 type PathValue struct {
-	Path  string
+	Path  Path
 	Value any
 }
 
 type PathFunc struct {
-	Path string
+	Path Path
 	Func reflect.Value
 }
 
@@ -25,7 +36,8 @@ type PathValueList []PathValue
 
 type PathFuncList []PathFunc
 
-func SanitizePathValueList(strict bool, args ...any) PathValueList {
+// SanitizePathValueList skips the paths that are not valid, which are reported as errors in strict mode.
+func SanitizePathValueList(strict bool, args ...any) (PathValueList, error) {
 	if len(args)%2 != 0 {
 		args = append(args, emptyValue)
 	}
@@ -33,11 +45,12 @@ func SanitizePathValueList(strict bool, args ...any) PathValueList {
 	list := make(PathValueList, len(args)/2)
 	arg := 0
 	invalidPathValues := 0
+	var err error
 	for i := 0; i < len(args); i += 2 {
-		path, ok := args[i].(string)
+		path, ok := sanitizePath(args[i])
 		if !ok {
 			if strict {
-				log.Panicf("invalid Path '%v'.  Paths must be string", args[i])
+				err = errors.Join(err, invalidPath(args[i]))
 			}
 			invalidPathValues += 1
 			continue
@@ -49,12 +62,12 @@ func SanitizePathValueList(strict bool, args ...any) PathValueList {
 		arg++
 	}
 	if invalidPathValues > 0 {
-		return list[:len(list)-invalidPathValues]
+		return list[:len(list)-invalidPathValues], err
 	}
-	return list
+	return list, err
 }
 
-func SanitizePathFuncList(strict bool, args ...any) PathFuncList {
+func SanitizePathFuncList(strict bool, args ...any) (PathFuncList, error) {
 	if len(args)%2 != 0 {
 		args = append(args, emptyFunc)
 	}
@@ -62,11 +75,12 @@ func SanitizePathFuncList(strict bool, args ...any) PathFuncList {
 	list := make(PathFuncList, len(args)/2)
 	arg := 0
 	invalidPathFuncs := 0
+	var err error
 	for i := 0; i < len(args); i += 2 {
-		path, ok := args[i].(string)
+		path, ok := sanitizePath(args[i])
 		if !ok {
 			if strict {
-				log.Panicf("invalid Path '%v'.  Paths must be string", args[i])
+				err = errors.Join(err, invalidPath(args[i]))
 			}
 			invalidPathFuncs += 1
 			continue
@@ -74,7 +88,7 @@ func SanitizePathFuncList(strict bool, args ...any) PathFuncList {
 		fn := reflect.ValueOf(args[i+1])
 		if fn.Kind() != reflect.Func {
 			if strict {
-				log.Panicf("invalid Func '%v'.  Paths must be a valid func(any)any", args[i+1])
+				err = errors.Join(err, &internal.ApplyError{Path: fmt.Sprint(path), Err: fmt.Errorf("invalid func '%v'", args[i+1])})
 			}
 			invalidPathFuncs += 1
 			continue
@@ -87,7 +101,19 @@ func SanitizePathFuncList(strict bool, args ...any) PathFuncList {
 		arg++
 	}
 	if invalidPathFuncs > 0 {
-		return list[:len(list)-invalidPathFuncs]
+		return list[:len(list)-invalidPathFuncs], err
+	}
+	return list, err
+}
+
+func invalidPath(arg any) error {
+	return &internal.PathError{Path: fmt.Sprint(arg), Reason: "paths must be strings"}
+}
+
+func sanitizePath(arg any) (Path, bool) {
+	switch arg.(type) {
+	case string, CompiledPath:
+		return arg, true
 	}
-	return list
+	return nil, false
 }
@@ -3,10 +3,19 @@ package sanitizer
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ivancorrales/knoa/internal"
 )
 
+type compiledPath string
+
+func (p compiledPath) Expr() string {
+	return string(p)
+}
+
 func Test_sanitizer_sanitize(t *testing.T) {
 	type fields struct {
 		strict bool
@@ -15,11 +24,11 @@ func Test_sanitizer_sanitize(t *testing.T) {
 		args []any
 	}
 	tests := []struct {
-		name     string
-		fields   fields
-		args     args
-		panicked bool
-		want     PathValueList
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		want    PathValueList
 	}{
 		{
 			name: "The list of args are correctly provide, there's nothing to be sanitized",
@@ -104,6 +113,31 @@ func Test_sanitizer_sanitize(t *testing.T) {
 				{"key2", "hello"},
 			},
 		},
+		{
+			name: "The list of args contains paths that are not strings but describe a path",
+			fields: fields{
+				strict: true,
+			},
+			args: args{
+				args: []any{compiledPath("parent.key1"), true},
+			},
+			want: PathValueList{
+				{compiledPath("parent.key1"), true},
+			},
+		},
+		{
+			name: "The list of args contains stringers, that are not paths",
+			fields: fields{
+				strict: true,
+			},
+			args: args{
+				args: []any{time.Second, 1, "key2", 2},
+			},
+			want: PathValueList{
+				{"key2", 2},
+			},
+			wantErr: true,
+		},
 		{
 			name: "The list of args contains non string keys, and enabled mode is enabled",
 			fields: fields{
@@ -112,17 +146,23 @@ func Test_sanitizer_sanitize(t *testing.T) {
 			args: args{
 				args: []any{20, "home", "key2"},
 			},
-			panicked: true,
+			want: PathValueList{
+				{"key2", emptyValue},
+			},
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.panicked {
-				assert.Panics(t, func() { SanitizePathValueList(tt.fields.strict, tt.args.args...) }, "The execution should end panicking")
+			got, err := SanitizePathValueList(tt.fields.strict, tt.args.args...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SanitizePathValueList() = %v, want %v", got, tt.want)
+			}
+			if tt.wantErr {
+				var pathErr *internal.PathError
+				assert.ErrorAs(t, err, &pathErr)
 			} else {
-				if got := SanitizePathValueList(tt.fields.strict, tt.args.args...); !reflect.DeepEqual(got, tt.want) {
-					t.Errorf("SanitizePathValueList() = %v, want %v", got, tt.want)
-				}
+				assert.NoError(t, err)
 			}
 		})
 	}
@@ -0,0 +1,238 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ivancorrales/knoa/inputter"
+	"github.com/ivancorrales/knoa/internal"
+)
+
+// Schema is a JSON Schema, draft 2020-12, compiled in advance to validate documents. Only the core
+// keywords are supported, and references must point to the same schema.
+type Schema struct {
+	root     any
+	patterns map[string]*regexp.Regexp
+	// refs are the references already compiled, as their targets could be anywhere in the schema.
+	refs map[string]bool
+}
+
+var types = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true, "number": true, "integer": true, "string": true,
+}
+
+// Compile accepts the schema as a map, a struct, or its JSON representation in a string or slice of bytes.
+func Compile(schema any) (*Schema, error) {
+	switch v := schema.(type) {
+	case *Schema:
+		return v, nil
+	case string:
+		return Compile([]byte(v))
+	case []byte:
+		root, err := inputter.NewJSON().Unmarshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+		schema = root
+	}
+	s := &Schema{root: internal.Normalize(schema), patterns: make(map[string]*regexp.Regexp), refs: make(map[string]bool)}
+	if err := s.compile(s.root, "#"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Schema) compile(node any, pointer string) error {
+	if _, ok := node.(bool); ok {
+		return nil
+	}
+	schema, ok := node.(map[string]any)
+	if !ok {
+		return invalidSchema(pointer, "schemas must be objects or booleans")
+	}
+	if err := s.compileKeywords(schema, pointer); err != nil {
+		return err
+	}
+	for _, keyword := range []string{"additionalProperties", "items", "not"} {
+		if sub, found := schema[keyword]; found {
+			if err := s.compile(sub, pointer+"/"+keyword); err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyword := range []string{"properties", "$defs", "definitions"} {
+		subs, found := schema[keyword]
+		if !found {
+			continue
+		}
+		m, ok := subs.(map[string]any)
+		if !ok {
+			return invalidSchema(pointer+"/"+keyword, "expected an object")
+		}
+		for _, key := range sortedKeys(m) {
+			if err := s.compile(m[key], pointer+"/"+keyword+"/"+escapeToken(key)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyword := range []string{"prefixItems", "allOf", "anyOf", "oneOf"} {
+		subs, found := schema[keyword]
+		if !found {
+			continue
+		}
+		items, ok := subs.([]any)
+		if !ok || len(items) == 0 {
+			return invalidSchema(pointer+"/"+keyword, "expected a non empty array")
+		}
+		for i := range items {
+			if err := s.compile(items[i], pointer+"/"+keyword+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) compileKeywords(schema map[string]any, pointer string) error {
+	if t, found := schema["type"]; found {
+		names, ok := typeNames(t)
+		if !ok {
+			return invalidSchema(pointer+"/type", fmt.Sprintf("unknown type '%v'", t))
+		}
+		for _, name := range names {
+			if !types[name] {
+				return invalidSchema(pointer+"/type", fmt.Sprintf("unknown type '%s'", name))
+			}
+		}
+	}
+	if p, found := schema["pattern"]; found {
+		expr, ok := p.(string)
+		if !ok {
+			return invalidSchema(pointer+"/pattern", "expected a string")
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return invalidSchema(pointer+"/pattern", err.Error())
+		}
+		s.patterns[expr] = re
+	}
+	if e, found := schema["enum"]; found {
+		if _, ok := e.([]any); !ok {
+			return invalidSchema(pointer+"/enum", "expected an array")
+		}
+	}
+	if r, found := schema["required"]; found {
+		if _, ok := stringList(r); !ok {
+			return invalidSchema(pointer+"/required", "expected an array of strings")
+		}
+	}
+	for _, keyword := range numericKeywords {
+		if value, found := schema[keyword]; found {
+			if _, ok := internal.ToFloat(value); !ok {
+				return invalidSchema(pointer+"/"+keyword, "expected a number")
+			}
+		}
+	}
+	if ref, found := schema["$ref"]; found {
+		expr, ok := ref.(string)
+		if !ok {
+			return invalidSchema(pointer+"/$ref", "expected a string")
+		}
+		target, err := s.resolve(expr)
+		if err != nil {
+			return invalidSchema(pointer+"/$ref", err.Error())
+		}
+		if !s.refs[expr] {
+			s.refs[expr] = true
+			if err := s.compile(target, expr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var numericKeywords = []string{
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+	"minLength", "maxLength", "minItems", "maxItems", "minProperties", "maxProperties",
+}
+
+// resolve follows a reference within the schema, this is, a JSON Pointer prefixed with '#'.
+func (s *Schema) resolve(ref string) (any, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("only references within the same schema are supported, found '%s'", ref)
+	}
+	node := s.root
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return node, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid reference '%s'", ref)
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = unescapeToken(token)
+		switch n := node.(type) {
+		case map[string]any:
+			next, found := n[token]
+			if !found {
+				return nil, fmt.Errorf("reference '%s' not found", ref)
+			}
+			node = next
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(n) {
+				return nil, fmt.Errorf("reference '%s' not found", ref)
+			}
+			node = n[index]
+		default:
+			return nil, fmt.Errorf("reference '%s' not found", ref)
+		}
+	}
+	return node, nil
+}
+
+func invalidSchema(pointer, reason string) error {
+	return fmt.Errorf("invalid schema at '%s': %s", pointer, reason)
+}
+
+func typeNames(t any) ([]string, bool) {
+	if name, ok := t.(string); ok {
+		return []string{name}, true
+	}
+	return stringList(t)
+}
+
+func stringList(value any) ([]string, bool) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, false
+	}
+	out := make([]string, len(items))
+	for i := range items {
+		if out[i], ok = items[i].(string); !ok {
+			return nil, false
+		}
+	}
+	return out, true
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escapeToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func unescapeToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
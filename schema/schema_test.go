@@ -0,0 +1,172 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const personSchema = `{
+	"$defs": {
+		"person": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {"type": "string", "minLength": 1, "maxLength": 10, "pattern": "^[A-Z]"},
+				"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+				"role": {"enum": ["admin", "user"]},
+				"siblings": {"type": "array", "maxItems": 3, "items": {"$ref": "#/$defs/person"}}
+			},
+			"additionalProperties": false
+		}
+	},
+	"$ref": "#/$defs/person"
+}`
+
+func Test_schema_validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   any
+		document any
+		want     []ValidationError
+	}{
+		{
+			name:     "A valid document",
+			schema:   personSchema,
+			document: map[string]any{"name": "Jane", "age": 30, "siblings": []any{map[string]any{"name": "Tim", "age": 20.0}}},
+		},
+		{
+			name:   "Errors in nested references",
+			schema: personSchema,
+			document: map[string]any{
+				"name": "jane",
+				"age":  30.5,
+				"role": "guest",
+				"siblings": []any{
+					map[string]any{"name": "Tim"},
+					map[string]any{"age": -1, "email": "x"},
+				},
+			},
+			want: []ValidationError{
+				{Path: "age", Keyword: "type", Message: "must be of type integer but is number"},
+				{Path: "name", Keyword: "pattern", Message: "must match the pattern '^[A-Z]'"},
+				{Path: "role", Keyword: "enum", Message: `must be one of ["admin","user"]`},
+				{Path: "siblings[1].name", Keyword: "required", Message: "is required"},
+				{Path: "siblings[1].age", Keyword: "minimum", Message: "must be greater than or equal to 0"},
+				{Path: "siblings[1].email", Keyword: "additionalProperties", Message: "is not allowed"},
+			},
+		},
+		{
+			name:     "Type of the whole document",
+			schema:   map[string]any{"type": []any{"object", "null"}},
+			document: []any{1},
+			want: []ValidationError{
+				{Keyword: "type", Message: "must be of type one of [object null] but is array"},
+			},
+		},
+		{
+			name: "Combinations",
+			schema: map[string]any{
+				"properties": map[string]any{
+					"port":    map[string]any{"anyOf": []any{map[string]any{"type": "integer"}, map[string]any{"type": "string", "pattern": "^[0-9]+$"}}},
+					"mode":    map[string]any{"oneOf": []any{map[string]any{"type": "string"}, map[string]any{"const": "auto"}}},
+					"name":    map[string]any{"allOf": []any{map[string]any{"minLength": 2}, map[string]any{"maxLength": 3}}},
+					"replica": map[string]any{"not": map[string]any{"const": 0}},
+				},
+			},
+			document: map[string]any{"port": "http", "mode": "auto", "name": "a", "replica": 0},
+			want: []ValidationError{
+				{Path: "mode", Keyword: "oneOf", Message: "must match exactly one of the schemas, but matches 2"},
+				{Path: "name", Keyword: "minLength", Message: "must have at least 2 characters"},
+				{Path: "port", Keyword: "anyOf", Message: "must match at least one of the schemas"},
+				{Path: "replica", Keyword: "not", Message: "must not match the schema"},
+			},
+		},
+		{
+			name: "Arrays with prefix items",
+			schema: map[string]any{
+				"prefixItems": []any{map[string]any{"type": "string"}},
+				"items":       map[string]any{"type": "number", "maximum": 10},
+				"minItems":    3,
+			},
+			document: []any{"a", 11},
+			want: []ValidationError{
+				{Keyword: "minItems", Message: "must have at least 3 items"},
+				{Path: "[1]", Keyword: "maximum", Message: "must be less than or equal to 10"},
+			},
+		},
+		{
+			name: "Quoted attributes and properties limits",
+			schema: map[string]any{
+				"maxProperties":        1,
+				"additionalProperties": map[string]any{"type": "boolean"},
+			},
+			document: map[string]any{"app.io/name": "api", "enabled": true},
+			want: []ValidationError{
+				{Keyword: "maxProperties", Message: "must have at most 1 attributes"},
+				{Path: `"app.io/name"`, Keyword: "type", Message: "must be of type boolean but is string"},
+			},
+		},
+		{
+			name: "References outside the definitions",
+			schema: map[string]any{
+				"properties": map[string]any{"name": map[string]any{"$ref": "#/components/name"}},
+				"components": map[string]any{"name": map[string]any{"pattern": "^a"}},
+			},
+			document: map[string]any{"name": "bob"},
+			want: []ValidationError{
+				{Path: "name", Keyword: "pattern", Message: "must match the pattern '^a'"},
+			},
+		},
+		{
+			name:     "Circular references",
+			schema:   map[string]any{"$defs": map[string]any{"a": map[string]any{"$ref": "#/$defs/a"}}, "$ref": "#/$defs/a"},
+			document: map[string]any{},
+			want: []ValidationError{
+				{Keyword: "$ref", Message: "can't be validated, the reference '#/$defs/a' is circular"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Compile(tt.schema)
+			assert.NoError(t, err)
+			err = s.Validate(tt.document)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var got []ValidationError
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var validationErr *ValidationError
+				assert.True(t, errors.As(e, &validationErr))
+				got = append(got, *validationErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_schema_compile(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema any
+		err    string
+	}{
+		{name: "Invalid JSON", schema: `{"type":`, err: "invalid schema: unexpected end of JSON input"},
+		{name: "Unknown type", schema: map[string]any{"properties": map[string]any{"a": map[string]any{"type": "int"}}}, err: "invalid schema at '#/properties/a/type': unknown type 'int'"},
+		{name: "Invalid pattern", schema: map[string]any{"pattern": "("}, err: "invalid schema at '#/pattern': error parsing regexp: missing closing ): `(`"},
+		{name: "Missing reference", schema: map[string]any{"$ref": "#/$defs/a"}, err: "invalid schema at '#/$ref': reference '#/$defs/a' not found"},
+		{name: "Invalid referenced schema", schema: map[string]any{"$ref": "#/components/name", "components": map[string]any{"name": map[string]any{"pattern": "("}}}, err: "invalid schema at '#/components/name/pattern': error parsing regexp: missing closing ): `(`"},
+		{name: "External reference", schema: map[string]any{"$ref": "person.json"}, err: "invalid schema at '#/$ref': only references within the same schema are supported, found 'person.json'"},
+		{name: "Invalid subschema", schema: map[string]any{"items": 1}, err: "invalid schema at '#/items': schemas must be objects or booleans"},
+		{name: "Invalid limit", schema: map[string]any{"minimum": "1"}, err: "invalid schema at '#/minimum': expected a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.schema)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
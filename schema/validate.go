@@ -0,0 +1,287 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"github.com/ivancorrales/knoa/internal"
+)
+
+// ValidationError reports a value of the document that doesn't satisfy the schema.
+type ValidationError struct {
+	// Path is the location of the value, i.e. siblings[1].age, empty for the whole document.
+	Path    string
+	Keyword string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("the document %s", e.Message)
+	}
+	return fmt.Sprintf("'%s' %s", e.Path, e.Message)
+}
+
+// Validate checks the document against the schema, and returns all the errors found.
+func (s *Schema) Validate(document any) error {
+	v := &validator{schema: s, refs: make(map[string]bool)}
+	errs := v.validate(s.root, "", internal.Normalize(document))
+	return errors.Join(errs...)
+}
+
+type validator struct {
+	schema *Schema
+	// refs are the references being evaluated for a given path, to detect the circular ones.
+	refs map[string]bool
+}
+
+func (v *validator) validate(node any, path string, value any) []error {
+	if allowed, ok := node.(bool); ok {
+		if allowed {
+			return nil
+		}
+		return []error{fail(path, "false", "is not allowed")}
+	}
+	schema, _ := node.(map[string]any)
+	var errs []error
+	if ref, found := schema["$ref"].(string); found {
+		errs = append(errs, v.validateRef(ref, path, value)...)
+	}
+	if t, found := schema["type"]; found {
+		names, _ := typeNames(t)
+		if !hasType(value, names) {
+			expected := names[0]
+			if len(names) > 1 {
+				expected = fmt.Sprintf("one of %v", names)
+			}
+			return append(errs, fail(path, "type", fmt.Sprintf("must be of type %s but is %s", expected, typeOf(value))))
+		}
+	}
+	errs = append(errs, v.validateValue(schema, path, value)...)
+	switch c := value.(type) {
+	case map[string]any:
+		errs = append(errs, v.validateObject(schema, path, c)...)
+	case []any:
+		errs = append(errs, v.validateArray(schema, path, c)...)
+	case string:
+		errs = append(errs, v.validateString(schema, path, c)...)
+	default:
+		if n, ok := internal.ToFloat(value); ok {
+			errs = append(errs, validateNumber(schema, path, n)...)
+		}
+	}
+	return append(errs, v.validateCombinations(schema, path, value)...)
+}
+
+func (v *validator) validateRef(ref, path string, value any) []error {
+	key := ref + " " + path
+	if v.refs[key] {
+		return []error{fail(path, "$ref", fmt.Sprintf("can't be validated, the reference '%s' is circular", ref))}
+	}
+	target, err := v.schema.resolve(ref)
+	if err != nil {
+		return []error{fail(path, "$ref", err.Error())}
+	}
+	v.refs[key] = true
+	defer delete(v.refs, key)
+	return v.validate(target, path, value)
+}
+
+func (v *validator) validateValue(schema map[string]any, path string, value any) []error {
+	var errs []error
+	if expected, found := schema["const"]; found && !internal.Equal(value, expected) {
+		errs = append(errs, fail(path, "const", fmt.Sprintf("must be %s", toJSON(expected))))
+	}
+	if enum, found := schema["enum"].([]any); found {
+		matches := false
+		for _, item := range enum {
+			if internal.Equal(value, item) {
+				matches = true
+				break
+			}
+		}
+		if !matches {
+			errs = append(errs, fail(path, "enum", fmt.Sprintf("must be one of %s", toJSON(enum))))
+		}
+	}
+	return errs
+}
+
+func (v *validator) validateObject(schema map[string]any, path string, value map[string]any) []error {
+	var errs []error
+	required, _ := stringList(schema["required"])
+	for _, name := range required {
+		if _, found := value[name]; !found {
+			errs = append(errs, fail(internal.AttributePath(path, name), "required", "is required"))
+		}
+	}
+	if n, found := limit(schema, "minProperties"); found && float64(len(value)) < n {
+		errs = append(errs, fail(path, "minProperties", fmt.Sprintf("must have at least %v attributes", n)))
+	}
+	if n, found := limit(schema, "maxProperties"); found && float64(len(value)) > n {
+		errs = append(errs, fail(path, "maxProperties", fmt.Sprintf("must have at most %v attributes", n)))
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	additional, hasAdditional := schema["additionalProperties"]
+	for _, key := range sortedKeys(value) {
+		childPath := internal.AttributePath(path, key)
+		if sub, found := properties[key]; found {
+			errs = append(errs, v.validate(sub, childPath, value[key])...)
+			continue
+		}
+		if hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				errs = append(errs, fail(childPath, "additionalProperties", "is not allowed"))
+				continue
+			}
+			errs = append(errs, v.validate(additional, childPath, value[key])...)
+		}
+	}
+	return errs
+}
+
+func (v *validator) validateArray(schema map[string]any, path string, value []any) []error {
+	var errs []error
+	if n, found := limit(schema, "minItems"); found && float64(len(value)) < n {
+		errs = append(errs, fail(path, "minItems", fmt.Sprintf("must have at least %v items", n)))
+	}
+	if n, found := limit(schema, "maxItems"); found && float64(len(value)) > n {
+		errs = append(errs, fail(path, "maxItems", fmt.Sprintf("must have at most %v items", n)))
+	}
+	prefixItems, _ := schema["prefixItems"].([]any)
+	items, hasItems := schema["items"]
+	for i := range value {
+		childPath := internal.IndexPath(path, i)
+		switch {
+		case i < len(prefixItems):
+			errs = append(errs, v.validate(prefixItems[i], childPath, value[i])...)
+		case hasItems:
+			if allowed, ok := items.(bool); ok && !allowed {
+				errs = append(errs, fail(childPath, "items", "is not allowed"))
+				continue
+			}
+			errs = append(errs, v.validate(items, childPath, value[i])...)
+		}
+	}
+	return errs
+}
+
+func (v *validator) validateString(schema map[string]any, path, value string) []error {
+	var errs []error
+	length := float64(utf8.RuneCountInString(value))
+	if n, found := limit(schema, "minLength"); found && length < n {
+		errs = append(errs, fail(path, "minLength", fmt.Sprintf("must have at least %v characters", n)))
+	}
+	if n, found := limit(schema, "maxLength"); found && length > n {
+		errs = append(errs, fail(path, "maxLength", fmt.Sprintf("must have at most %v characters", n)))
+	}
+	if expr, found := schema["pattern"].(string); found && !v.schema.patterns[expr].MatchString(value) {
+		errs = append(errs, fail(path, "pattern", fmt.Sprintf("must match the pattern '%s'", expr)))
+	}
+	return errs
+}
+
+func validateNumber(schema map[string]any, path string, value float64) []error {
+	var errs []error
+	if n, found := limit(schema, "minimum"); found && value < n {
+		errs = append(errs, fail(path, "minimum", fmt.Sprintf("must be greater than or equal to %v", n)))
+	}
+	if n, found := limit(schema, "maximum"); found && value > n {
+		errs = append(errs, fail(path, "maximum", fmt.Sprintf("must be less than or equal to %v", n)))
+	}
+	if n, found := limit(schema, "exclusiveMinimum"); found && value <= n {
+		errs = append(errs, fail(path, "exclusiveMinimum", fmt.Sprintf("must be greater than %v", n)))
+	}
+	if n, found := limit(schema, "exclusiveMaximum"); found && value >= n {
+		errs = append(errs, fail(path, "exclusiveMaximum", fmt.Sprintf("must be less than %v", n)))
+	}
+	return errs
+}
+
+func (v *validator) validateCombinations(schema map[string]any, path string, value any) []error {
+	var errs []error
+	if all, found := schema["allOf"].([]any); found {
+		for _, sub := range all {
+			errs = append(errs, v.validate(sub, path, value)...)
+		}
+	}
+	if anyOf, found := schema["anyOf"].([]any); found && v.matches(anyOf, path, value) == 0 {
+		errs = append(errs, fail(path, "anyOf", "must match at least one of the schemas"))
+	}
+	if oneOf, found := schema["oneOf"].([]any); found {
+		if matches := v.matches(oneOf, path, value); matches != 1 {
+			errs = append(errs, fail(path, "oneOf", fmt.Sprintf("must match exactly one of the schemas, but matches %d", matches)))
+		}
+	}
+	if not, found := schema["not"]; found && len(v.validate(not, path, value)) == 0 {
+		errs = append(errs, fail(path, "not", "must not match the schema"))
+	}
+	return errs
+}
+
+func (v *validator) matches(schemas []any, path string, value any) int {
+	matches := 0
+	for _, sub := range schemas {
+		if len(v.validate(sub, path, value)) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func fail(path, keyword, message string) error {
+	return &ValidationError{Path: path, Keyword: keyword, Message: message}
+}
+
+func limit(schema map[string]any, keyword string) (float64, bool) {
+	value, found := schema[keyword]
+	if !found {
+		return 0, false
+	}
+	return internal.ToFloat(value)
+}
+
+func hasType(value any, names []string) bool {
+	actual := typeOf(value)
+	for _, name := range names {
+		if name == actual || (name == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type of the value, where numbers without decimals are integers.
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	}
+	n, ok := internal.ToFloat(value)
+	if !ok {
+		return internal.KindOf(value)
+	}
+	if n == math.Trunc(n) {
+		return "integer"
+	}
+	return "number"
+}
+
+func toJSON(value any) string {
+	out, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(out)
+}
@@ -0,0 +1,286 @@
+package knoa
+
+import (
+	"sync"
+
+	"github.com/ivancorrales/knoa/internal"
+	"github.com/ivancorrales/knoa/mutator"
+	"github.com/ivancorrales/knoa/outputter"
+)
+
+// Sync returns a document that can be safely shared between goroutines. Operations take a write
+// lock, while the getters and the outputs take a read lock once the pending operations are applied.
+func Sync[T Type](k Knoa[T]) Knoa[T] {
+	if doc, ok := k.(*syncKnoa[T]); ok {
+		return doc
+	}
+	return &syncKnoa[T]{doc: unwrap(k)}
+}
+
+type syncKnoa[T Type] struct {
+	mu  sync.RWMutex
+	doc *knoa[T]
+}
+
+// write runs the operation holding the write lock. In immutable mode the returned document is synchronized too.
+func (s *syncKnoa[T]) write(fn func(doc *knoa[T]) Knoa[T]) Knoa[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if doc, ok := fn(s.doc).(*knoa[T]); ok && doc != s.doc {
+		return &syncKnoa[T]{doc: doc}
+	}
+	return s
+}
+
+// read applies the pending operations and then runs fn holding the read lock. fn must take the
+// content it receives, instead of materializing it again, and return the errors instead of recording them.
+func (s *syncKnoa[T]) read(fn func(doc *knoa[T], content T) error) {
+	s.materialize()
+	err := func() error {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return fn(s.doc, s.doc.out)
+	}()
+	if err != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.doc.record(err)
+	}
+}
+
+func (s *syncKnoa[T]) materialize() {
+	s.mu.RLock()
+	pending := s.doc.pending()
+	s.mu.RUnlock()
+	if pending {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.doc.materialize()
+	}
+}
+
+func (s *syncKnoa[T]) Set(args ...any) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Set(args...)
+	})
+}
+
+func (s *syncKnoa[T]) Unset(paths ...string) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Unset(paths...)
+	})
+}
+
+func (s *syncKnoa[T]) UnsetPaths(paths ...Path) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.UnsetPaths(paths...)
+	})
+}
+
+func (s *syncKnoa[T]) Apply(args ...any) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Apply(args...)
+	})
+}
+
+func (s *syncKnoa[T]) ApplyCtx(path any, fn func(ctx NodeContext) any) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.ApplyCtx(path, fn)
+	})
+}
+
+func (s *syncKnoa[T]) Insert(path string, index int, values ...any) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Insert(path, index, values...)
+	})
+}
+
+func (s *syncKnoa[T]) Move(from, to string) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Move(from, to)
+	})
+}
+
+func (s *syncKnoa[T]) Copy(from, to string) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Copy(from, to)
+	})
+}
+
+func (s *syncKnoa[T]) Rename(path, name string) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Rename(path, name)
+	})
+}
+
+func (s *syncKnoa[T]) Merge(other any, opts ...mutator.MergeOpt) Knoa[T] {
+	// the other document is read before taking the lock, as it could be this same document
+	content := mergeContent(other)
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Merge(content, opts...)
+	})
+}
+
+func (s *syncKnoa[T]) Patch(patch []byte) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Patch(patch)
+	})
+}
+
+func (s *syncKnoa[T]) MergePatch(patch map[string]any) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.MergePatch(patch)
+	})
+}
+
+func (s *syncKnoa[T]) With(opts ...mutator.OperationOpt) func(pathValueList ...any) Knoa[T] {
+	return func(args ...any) Knoa[T] {
+		return s.write(func(doc *knoa[T]) Knoa[T] {
+			return doc.With(opts...)(args...)
+		})
+	}
+}
+
+func (s *syncKnoa[T]) Clone() Knoa[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &syncKnoa[T]{doc: s.doc.clone()}
+}
+
+// Tx holds the write lock while fn runs, so fn must only use the given tx.
+func (s *syncKnoa[T]) Tx(fn func(tx Knoa[T]) error) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Tx(fn)
+	})
+}
+
+func (s *syncKnoa[T]) Snapshot(name string) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Snapshot(name)
+	})
+}
+
+func (s *syncKnoa[T]) Restore(name string) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Restore(name)
+	})
+}
+
+func (s *syncKnoa[T]) Undo() Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Undo()
+	})
+}
+
+func (s *syncKnoa[T]) Redo() Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.Redo()
+	})
+}
+
+func (s *syncKnoa[T]) History() []HistoryEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.doc.History()
+}
+
+func (s *syncKnoa[T]) Operations() []Operation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.doc.Operations()
+}
+
+func (s *syncKnoa[T]) replay(ops []Operation) Knoa[T] {
+	return s.write(func(doc *knoa[T]) Knoa[T] {
+		return doc.replay(ops)
+	})
+}
+
+func (s *syncKnoa[T]) Get(path any) (value any, found bool) {
+	s.read(func(doc *knoa[T], content T) (err error) {
+		value, found, err = doc.get(content, path)
+		return err
+	})
+	return value, found
+}
+
+func (s *syncKnoa[T]) Has(path any) bool {
+	_, found := s.Get(path)
+	return found
+}
+
+func (s *syncKnoa[T]) GetString(path any) (string, bool) {
+	return toString(s.Get(path))
+}
+
+func (s *syncKnoa[T]) GetInt(path any) (int, bool) {
+	return toInt(s.Get(path))
+}
+
+func (s *syncKnoa[T]) GetFloat(path any) (float64, bool) {
+	return toFloat(s.Get(path))
+}
+
+func (s *syncKnoa[T]) GetBool(path any) (bool, bool) {
+	return toBool(s.Get(path))
+}
+
+func (s *syncKnoa[T]) GetSlice(path any) ([]any, bool) {
+	return toSlice(s.Get(path))
+}
+
+func (s *syncKnoa[T]) GetMap(path any) (map[string]any, bool) {
+	return toMap(s.Get(path))
+}
+
+func (s *syncKnoa[T]) Out() (out T) {
+	s.read(func(_ *knoa[T], content T) error {
+		out, _ = internal.Normalize(content).(T)
+		return nil
+	})
+	return out
+}
+
+func (s *syncKnoa[T]) YAML(opts ...outputter.YAMLOpt) (str string) {
+	s.read(func(_ *knoa[T], content T) (err error) {
+		str, err = outputter.NewYAML(opts...).Marshal(content)
+		return err
+	})
+	return str
+}
+
+func (s *syncKnoa[T]) JSON(opts ...outputter.JSONOpt) (str string) {
+	s.read(func(_ *knoa[T], content T) (err error) {
+		str, err = outputter.NewJSON(opts...).Marshal(content)
+		return err
+	})
+	return str
+}
+
+func (s *syncKnoa[T]) JSONPatch(opts ...outputter.JSONOpt) (str string) {
+	s.read(func(doc *knoa[T], _ T) (err error) {
+		str, err = doc.jsonPatch(opts...)
+		return err
+	})
+	return str
+}
+
+func (s *syncKnoa[T]) To(out interface{}) {
+	s.read(func(_ *knoa[T], content T) error {
+		return decodeTo(internal.Normalize(content), out)
+	})
+}
+
+func (s *syncKnoa[T]) Validate(schema any) (err error) {
+	s.read(func(_ *knoa[T], content T) error {
+		err = validate(schema, content)
+		return nil
+	})
+	return err
+}
+
+func (s *syncKnoa[T]) Error() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.doc.Error()
+}